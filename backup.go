@@ -0,0 +1,33 @@
+package conf
+
+import (
+	"errors"
+	"os"
+)
+
+// KeepBackup enables copying the existing config file to a backup file
+// with the suffix ".bak" before it is overwritten by Write.
+func (b *Builder) KeepBackup() *Builder {
+	b.ctx.KeepBackup = true
+	return b
+}
+
+// backup copies the config file to its backup file, if it exists.
+func (c *Context) backup() error {
+	data, err := c.readFile(c.Path())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	f, err := c.fsys().OpenFile(c.Path()+".bak", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, c.fileMode())
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
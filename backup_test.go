@@ -0,0 +1,37 @@
+package conf
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestKeepBackup(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().KeepBackup().Create()
+
+	// No backup for a new config
+	first := TestConfig{String: "first"}
+	if err := conf.Write(first); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(conf.Path() + ".bak"); !os.IsNotExist(err) {
+		t.Errorf("Unexpected backup: %v", err)
+	}
+
+	if err := conf.Write(TestConfig{String: "second"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The backup contains the first version
+	expected, err := conf.marshal(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(conf.Path() + ".bak")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(expected) {
+		t.Errorf("Unexpected backup: %s", data)
+	}
+}
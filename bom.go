@@ -0,0 +1,26 @@
+package conf
+
+import (
+	"bytes"
+)
+
+// bom is the UTF-8 byte order mark, which some editors on Windows write
+// at the start of text files.
+var bom = []byte{0xEF, 0xBB, 0xBF}
+
+// binaryFormats are the formats whose encoded data is not text.
+var binaryFormats = map[Format]bool{
+	FormatGob:     true,
+	FormatCBOR:    true,
+	FormatMsgPack: true,
+	FormatProto:   true,
+}
+
+// stripBOM removes a leading byte order mark from encoded data, unless
+// the encoding of the context is binary.
+func (c *Context) stripBOM(data []byte) []byte {
+	if binaryFormats[c.format] {
+		return data
+	}
+	return bytes.TrimPrefix(data, bom)
+}
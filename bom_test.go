@@ -0,0 +1,18 @@
+package conf
+
+import (
+	"testing"
+)
+
+func TestStripBOM(t *testing.T) {
+	// Initialize config context with a BOM-prefixed fixture
+	conf := Build().Directory("testdata/bom").JSON().Create()
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if cfg != cfgRead {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+}
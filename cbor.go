@@ -0,0 +1,13 @@
+package conf
+
+import (
+	"github.com/fxamacker/cbor/v2"
+)
+
+// CBOR sets the encoding to the binary CBOR format.
+func (b *Builder) CBOR() *Builder {
+	if b.ctx.File == "" {
+		b.ctx.File = "config.cbor"
+	}
+	return b.codec(FormatCBOR, cbor.Marshal, cbor.Unmarshal)
+}
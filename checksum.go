@@ -0,0 +1,106 @@
+package conf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ErrChecksum is returned by Read if the config file does not match its
+// checksum file.
+var ErrChecksum = errors.New("Config file does not match its checksum")
+
+// Checksum enables writing the SHA-256 hash of the config file to a
+// checksum file with the suffix ".sha256" on Write, in the format of
+// sha256sum. Read verifies the config file against its checksum file and
+// returns ErrChecksum if they differ, e.g. after manual edits. A missing
+// checksum file is not considered an error.
+//
+// Both files are replaced atomically, but not together, so readers in
+// other processes need FileLock to never see a mismatch during Write.
+func (b *Builder) Checksum() *Builder {
+	b.ctx.Checksum = true
+	return b
+}
+
+// checksumPath returns the path of the checksum file of the file at path.
+func checksumPath(path string) string {
+	return path + ".sha256"
+}
+
+// checksumWriter returns a writer that writes to w and, if checksums are
+// enabled, hashes the written data. Once all data is written, prepareSum
+// writes the checksum file for the file at path to a temporary file and
+// returns its path, which commitChecksum renames into place. The path is
+// empty if checksums are disabled.
+func (c *Context) checksumWriter(w io.Writer, path string) (hw io.Writer, prepareSum func() (string, error)) {
+	if !c.Checksum {
+		return w, func() (string, error) { return "", nil }
+	}
+	h := sha256.New()
+	return io.MultiWriter(w, h), func() (string, error) {
+		return c.writeChecksum(path, h)
+	}
+}
+
+// writeChecksum writes the sum of h to a temporary checksum file for path
+// and returns its path.
+func (c *Context) writeChecksum(path string, h hash.Hash) (string, error) {
+	tmp := tempPath(checksumPath(path))
+	f, err := c.fsys().OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_EXCL, c.fileMode())
+	if err != nil {
+		return "", err
+	}
+	_, err = fmt.Fprintf(f, "%x  %s\n", h.Sum(nil), filepath.Base(path))
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		c.fsys().Remove(tmp)
+		return "", err
+	}
+	return tmp, nil
+}
+
+// commitChecksum renames the temporary checksum file tmp for path into
+// place. It does nothing if tmp is empty.
+func (c *Context) commitChecksum(path, tmp string) error {
+	if tmp == "" {
+		return nil
+	}
+	return c.fsys().Rename(tmp, checksumPath(path))
+}
+
+// verifyChecksum compares data, the contents of the file at path, with
+// the checksum file of path, if it exists.
+func (c *Context) verifyChecksum(path string, data []byte) error {
+	sidecar, err := c.readFile(checksumPath(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fields := bytes.Fields(sidecar)
+	if len(fields) == 0 {
+		return ErrChecksum
+	}
+	expected, err := hex.DecodeString(string(fields[0]))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrChecksum, err)
+	}
+	sum := sha256.Sum256(data)
+	if !bytes.Equal(expected, sum[:]) {
+		return ErrChecksum
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package conf
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestChecksum(t *testing.T) {
+	// Initialize config context
+	conf := Build().Directory(t.TempDir()).JSON().Checksum().Create()
+
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+	data, err := conf.ReadBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sidecar, err := ioutil.ReadFile(conf.Path() + ".sha256")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := fmt.Sprintf("%x  config.json\n", sha256.Sum256(data))
+	if string(sidecar) != expected {
+		t.Errorf("Unexpected checksum file: %q", sidecar)
+	}
+
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfg != cfgRead {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+
+	// A tampered config file is detected
+	if err := ioutil.WriteFile(conf.Path(), []byte(`{"String": "tampered"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := conf.Read(&cfgRead); !errors.Is(err, ErrChecksum) {
+		t.Errorf("Expected ErrChecksum, got %v", err)
+	}
+
+	// A missing checksum file is tolerated
+	if err := os.Remove(conf.Path() + ".sha256"); err != nil {
+		t.Fatal(err)
+	}
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead.String != "tampered" {
+		t.Errorf("Unexpected config: %v", cfgRead)
+	}
+}
+
+func TestChecksumStream(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Checksum().Create()
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if err := conf.WriteStream(cfg); err != nil {
+		t.Fatal(err)
+	}
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conf.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(conf.Path() + ".sha256"); !os.IsNotExist(err) {
+		t.Errorf("Expected checksum file to be deleted: %v", err)
+	}
+}
+
+// sidecarFailFS fails to create checksum files.
+type sidecarFailFS struct {
+	osFS
+}
+
+func (sidecarFailFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	if strings.Contains(name, ".sha256") {
+		return nil, errors.New("disk full")
+	}
+	return osFS{}.OpenFile(name, flag, perm)
+}
+
+func TestChecksumWriteError(t *testing.T) {
+	// Initialize config context
+	dir := t.TempDir()
+	conf := Build().Directory(dir).JSON().Checksum().Create()
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failing checksum file keeps the old config file
+	failing := Build().Directory(dir).JSON().Checksum().FileSystem(sidecarFailFS{}).Create()
+	if err := failing.Write(TestConfig{String: "new"}); err == nil {
+		t.Fatal("Expected error")
+	}
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfg != cfgRead {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+
+	// No temporary files are left behind
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Errorf("Unexpected files: %v", files)
+	}
+}
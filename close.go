@@ -0,0 +1,58 @@
+package conf
+
+import (
+	"io"
+	"sync"
+)
+
+var _ io.Closer = (*Context)(nil)
+
+// closers holds the functions that release the resources of a context
+// on Close, e.g. watchers and debounced writers.
+type closers struct {
+	mu   sync.Mutex
+	next int
+	fns  map[int]func() error
+}
+
+// onClose registers fn to be called by Close. The returned function
+// unregisters it again.
+func (c *Context) onClose(fn func() error) (remove func()) {
+	c.closers.mu.Lock()
+	defer c.closers.mu.Unlock()
+	if c.closers.fns == nil {
+		c.closers.fns = make(map[int]func() error)
+	}
+	id := c.closers.next
+	c.closers.next++
+	c.closers.fns[id] = fn
+	return func() {
+		c.closers.mu.Lock()
+		delete(c.closers.fns, id)
+		c.closers.mu.Unlock()
+	}
+}
+
+// Close flushes pending debounced writes and stops all watchers of the
+// context, in reverse order of their creation. It should be called before
+// the program exits when using Watch or DebouncedWriter. Locks are only
+// held during single reads and writes, so there are none to release. For
+// other contexts, Close does nothing. It returns the first error.
+func (c *Context) Close() error {
+	c.closers.mu.Lock()
+	fns, next := c.closers.fns, c.closers.next
+	c.closers.fns = nil
+	c.closers.mu.Unlock()
+
+	var first error
+	for id := next - 1; id >= 0; id-- {
+		fn, ok := fns[id]
+		if !ok {
+			continue
+		}
+		if err := fn(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
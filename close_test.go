@@ -0,0 +1,39 @@
+package conf
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClose(t *testing.T) {
+	// A plain context has nothing to close
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+	if err := conf.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	write, _, _ := conf.DebouncedWriter(time.Hour)
+	var cfgWatched TestConfig
+	if _, err := conf.Watch(&cfgWatched, func(error) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Close flushes the pending write
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	write(cfg)
+	if err := conf.Close(); err != nil {
+		t.Fatal(err)
+	}
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead != cfg {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+
+	// Closing again does nothing
+	if err := conf.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
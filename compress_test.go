@@ -0,0 +1,46 @@
+package conf
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func TestCompressed(t *testing.T) {
+	for _, b := range []*Builder{Build().JSON(), Build().Gob()} {
+		conf := b.Directory(t.TempDir()).Compressed().Create()
+
+		cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+		if err := conf.Write(cfg); err != nil {
+			t.Fatal(err)
+		}
+
+		// The file is gzip compressed
+		data, err := ioutil.ReadFile(conf.Path())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+			t.Errorf("%s is not gzip compressed", conf.File)
+		}
+
+		var cfgRead TestConfig
+		if err := conf.Read(&cfgRead); err != nil {
+			t.Fatal(err)
+		}
+		if cfg != cfgRead {
+			t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+		}
+	}
+}
+
+func TestCompressedFileName(t *testing.T) {
+	conf := Build().JSON().Compressed().Create()
+	if conf.File != "config.json.gz" {
+		t.Errorf("Unexpected file name: %s", conf.File)
+	}
+	conf = Build().File("config.json.gz").JSON().Compressed().Create()
+	if conf.File != "config.json.gz" {
+		t.Errorf("Unexpected file name: %s", conf.File)
+	}
+}
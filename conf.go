@@ -19,10 +19,20 @@
 package conf
 
 import (
+	"bytes"
+	"io"
+	"io/fs"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"reflect"
 	"encoding/json"
 	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Marshal returns a encoding of v.
@@ -30,64 +40,908 @@ type MarshalFunc func(v interface{}) ([]byte, error)
 // Unmarshal parses the data and stores the result in the value pointed to by v.
 type UnmarshalFunc func(data []byte, v interface{}) error
 
-// Context holds all information to access a specific config file
+// ConfMarshaler is implemented by config types that encode themselves.
+// Write uses MarshalConf instead of the encoding of the context.
+type ConfMarshaler interface {
+	MarshalConf() ([]byte, error)
+}
+
+// ConfUnmarshaler is implemented by config types that decode themselves.
+// Read uses UnmarshalConf instead of the encoding of the context.
+type ConfUnmarshaler interface {
+	UnmarshalConf(data []byte) error
+}
+
+// Context holds all information to access a specific config file.
+// It is safe to read and write the config file from multiple goroutines.
 type Context struct {
 	Directory string
 	File string
 	Marshal MarshalFunc
 	Unmarshal UnmarshalFunc
+	FileMode os.FileMode
+	DirMode os.FileMode
+	Defaults interface{}
+	SearchPaths []string
+	EnvPrefix string
+	Compressed bool
+	EncryptionKey []byte
+	KeepBackup bool
+	Validator func(conf interface{}) error
+	HTTPTimeout time.Duration
+	NewEncoder func(w io.Writer) StreamEncoder
+	FileLock bool
+	OnRead func(data []byte) ([]byte, error)
+	OnWrite func(data []byte) ([]byte, error)
+	Profile string
+	Migrations []func(m map[string]interface{}) error
+	Shards map[string]string
+	ReadOnly bool
+	EmbeddedFS fs.FS
+	EmbeddedFile string
+	RetryAttempts int
+	RetryBackoff time.Duration
+	FileSystem FileSystem
+	NoCreateDir bool
+	Header string
+	TempDir string
+	ReadMetrics MetricsFunc
+	WriteMetrics MetricsFunc
+	Logger LogFunc
+	MergeSlices SliceMerge
+	Checksum bool
+	EnvOnly bool
+	LineEnding string
+	NormalizeRead bool
+
+	format Format
+	mu sync.RWMutex
+	closers closers
 }
 
 var (
 	ErrNoMarshal = errors.New("Context has no marshal func")
 	ErrNoUnmarshal = errors.New("Context has no Unmarshal func")
+	ErrNotPointer = errors.New("Config is not a pointer")
+	ErrNotStruct = errors.New("Config is not a pointer to a struct")
+	ErrNoDefaults = errors.New("Context has no defaults")
 )
 
+// ConfigError records an error and the operation and config file
+// that caused it.
+type ConfigError struct {
+	Op string
+	Path string
+	Err error
+}
+
+func (e *ConfigError) Error() string {
+	return e.Op + " " + e.Path + ": " + e.Err.Error()
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// wrapErr wraps a non-nil err in a ConfigError for the config file
+// of the context.
+func (c *Context) wrapErr(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ConfigError{op, c.Path(), err}
+}
+
+// Path returns the path of the config file, including the profile.
+func (c *Context) Path() string {
+	return filepath.Join(c.Directory, c.fileName())
+}
+
+// Clone returns a shallow copy of the context.
+func (c *Context) Clone() *Context {
+	return &Context{
+		Directory: c.Directory,
+		File: c.File,
+		Marshal: c.Marshal,
+		Unmarshal: c.Unmarshal,
+		FileMode: c.FileMode,
+		DirMode: c.DirMode,
+		Defaults: c.Defaults,
+		SearchPaths: c.SearchPaths,
+		EnvPrefix: c.EnvPrefix,
+		Compressed: c.Compressed,
+		EncryptionKey: c.EncryptionKey,
+		KeepBackup: c.KeepBackup,
+		Validator: c.Validator,
+		HTTPTimeout: c.HTTPTimeout,
+		NewEncoder: c.NewEncoder,
+		FileLock: c.FileLock,
+		OnRead: c.OnRead,
+		OnWrite: c.OnWrite,
+		Profile: c.Profile,
+		Migrations: c.Migrations,
+		Shards: c.Shards,
+		ReadOnly: c.ReadOnly,
+		EmbeddedFS: c.EmbeddedFS,
+		EmbeddedFile: c.EmbeddedFile,
+		RetryAttempts: c.RetryAttempts,
+		RetryBackoff: c.RetryBackoff,
+		FileSystem: c.FileSystem,
+		NoCreateDir: c.NoCreateDir,
+		Header: c.Header,
+		TempDir: c.TempDir,
+		ReadMetrics: c.ReadMetrics,
+		WriteMetrics: c.WriteMetrics,
+		Logger: c.Logger,
+		MergeSlices: c.MergeSlices,
+		Checksum: c.Checksum,
+		EnvOnly: c.EnvOnly,
+		LineEnding: c.LineEnding,
+		NormalizeRead: c.NormalizeRead,
+		format: c.format,
+	}
+}
+
+// WithFile returns a copy of the context for another config file in the
+// same directory.
+func (c *Context) WithFile(file string) *Context {
+	clone := c.Clone()
+	clone.File = file
+	return clone
+}
+
 // Read reads the config file into the value pointed to by conf.
+// If the context has defaults, they are applied to conf first, so fields
+// missing in the config file keep their default value.
+// Fields tagged with `conf:",required"` must not be left at their zero
+// value, and if the context has a validator, it is called afterwards.
 func (c *Context) Read(conf interface{}) error {
-	f, err := os.Open(c.Directory + "/" + c.File)
+	unlock, err := c.rlock()
+	if err != nil {
+		return c.wrapErr("read", err)
+	}
+	defer unlock()
+	return c.wrapErr("read", c.read(conf))
+}
+
+func (c *Context) read(conf interface{}) error {
+	return c.observeRead(func() (int, error) {
+		return c.readDefaults(conf, c.Defaults)
+	})
+}
+
+// observeRead runs fn with retries, logging and metrics. fn returns the
+// number of bytes read.
+func (c *Context) observeRead(fn func() (int, error)) error {
+	c.log("read.start", "path", c.Path())
+	start, size := time.Now(), 0
+	err := c.retry(func() (err error) {
+		size, err = fn()
+		return err
+	})
+	c.log("read.done", "path", c.Path(), "bytes", size, "err", err)
+	observe(c.ReadMetrics, start, size, err)
+	return err
+}
+
+// readDefaults reads the config file into conf, after applying defaults.
+// It returns the size of the config file.
+func (c *Context) readDefaults(conf, defaults interface{}) (size int, err error) {
+	if defaults != nil {
+		if err := c.applyDefaults(conf, defaults); err != nil {
+			return 0, err
+		}
+	}
+	if c.EnvOnly {
+		if err := c.setEnv(conf); err != nil {
+			return 0, err
+		}
+		return 0, c.validate(conf)
+	}
+	path := c.readPath()
+	if c.EmbeddedFS != nil && !c.stdio() && c.missing(path) {
+		c.log("read.embedded", "path", path, "embedded", c.EmbeddedFile)
+		if err := c.decodeEmbedded(conf); err != nil {
+			return 0, err
+		}
+		return 0, c.validate(conf)
+	}
+	bytes, err := c.loadFile(path)
 	if err != nil {
-		path := err.(*os.PathError)
-		if path != nil && path.Err == os.ErrNotExist {
+		return 0, err
+	}
+	if bytes != nil {
+		if err := c.unmarshal(bytes, conf); err != nil {
+			return len(bytes), err
+		}
+	}
+	return len(bytes), c.validate(conf)
+}
+
+// ReadOrDefault reads the config file into the value pointed to by conf
+// like Read, but uses the given defaults instead of the defaults of the
+// context. If the config file does not exist, conf is set to defaults.
+func (c *Context) ReadOrDefault(conf, defaults interface{}) error {
+	unlock, err := c.rlock()
+	if err != nil {
+		return c.wrapErr("read", err)
+	}
+	defer unlock()
+	_, err = c.readDefaults(conf, defaults)
+	return c.wrapErr("read", err)
+}
+
+// validate checks the required fields of conf and runs the validator of
+// the context on it, if any.
+func (c *Context) validate(conf interface{}) error {
+	if err := checkRequired(conf); err != nil {
+		return err
+	}
+	if c.Validator == nil {
+		return nil
+	}
+	return c.Validator(conf)
+}
+
+// decodeFile decodes the file at path into the value pointed to by conf.
+// A missing file is not considered an error.
+func (c *Context) decodeFile(path string, conf interface{}) error {
+	bytes, err := c.loadFile(path)
+	if bytes == nil || err != nil {
+		return err
+	}
+	return c.unmarshal(bytes, conf)
+}
+
+// loadFile returns the contents of the file at path, or of standard input
+// for Stdio. A missing file has nil contents.
+func (c *Context) loadFile(path string) ([]byte, error) {
+	if c.stdio() {
+		return ioutil.ReadAll(os.Stdin)
+	}
+	bytes, err := c.readFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		c.log("read.missing", "path", path)
+		return nil, nil
+	}
+	if err == nil && c.Checksum {
+		err = c.verifyChecksum(path, bytes)
+	}
+	return bytes, err
+}
+
+// maxPrealloc limits the buffer preallocated by readAll.
+const maxPrealloc = 64 << 20
+
+// readAll reads the whole file into a buffer preallocated to its size,
+// to avoid repeatedly growing the buffer for large files.
+func readAll(f File) ([]byte, error) {
+	var size int64
+	if fi, err := f.Stat(); err == nil {
+		size = fi.Size()
+	}
+	if size > maxPrealloc {
+		size = maxPrealloc
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, size + bytes.MinRead))
+	_, err := buf.ReadFrom(f)
+	return buf.Bytes(), err
+}
+
+// ReadMerged reads the config file from each of the search paths of the
+// context in order and merges them into the value pointed to by conf, so
+// values in later files override those in earlier ones. Nested objects
+// are merged key by key, while slices are replaced or appended as set by
+// Builder.MergeSlices. Missing files are skipped. If the encoding cannot
+// decode into maps, e.g. INI, each file is decoded onto conf instead.
+// Note that Write still only writes to the config file in Directory.
+func (c *Context) ReadMerged(conf interface{}) error {
+	unlock, err := c.rlock()
+	if err != nil {
+		return c.wrapErr("read", err)
+	}
+	defer unlock()
+	return c.wrapErr("read", c.observeRead(func() (int, error) {
+		return c.readMerged(conf)
+	}))
+}
+
+// readMerged merges the config files of the search paths into conf, after
+// applying defaults. It returns the total size of the config files.
+func (c *Context) readMerged(conf interface{}) (size int, err error) {
+	if c.Defaults != nil {
+		if err := c.applyDefaults(conf, c.Defaults); err != nil {
+			return 0, err
+		}
+	}
+	if _, ok := conf.(ConfUnmarshaler); ok {
+		size, err = c.decodeLayers(conf)
+	} else if size, err = c.mergeLayers(conf); err != nil {
+		size, err = c.decodeLayers(conf)
+	}
+	if err != nil {
+		return size, err
+	}
+	return size, c.validate(conf)
+}
+
+// mergeLayers decodes the config file of each search path into a map,
+// merges the maps and copies the result into the value pointed to by conf.
+func (c *Context) mergeLayers(conf interface{}) (size int, err error) {
+	if c.Marshal == nil {
+		return 0, ErrNoMarshal
+	}
+	merged := make(map[string]interface{})
+	for _, dir := range c.SearchPaths {
+		m := make(map[string]interface{})
+		n, err := c.decodeLayer(dir, &m)
+		size += n
+		if err != nil {
+			return size, err
+		}
+		deepMerge(merged, m, c.MergeSlices)
+	}
+	if len(merged) == 0 {
+		return size, nil
+	}
+	return size, c.applyDefaults(conf, merged)
+}
+
+// decodeLayers decodes the config file of each search path onto the value
+// pointed to by conf in order.
+func (c *Context) decodeLayers(conf interface{}) (size int, err error) {
+	for _, dir := range c.SearchPaths {
+		n, err := c.decodeLayer(dir, conf)
+		size += n
+		if err != nil {
+			return size, err
+		}
+	}
+	return size, nil
+}
+
+// decodeLayer decodes the config file in dir into conf and returns its
+// size. A missing file is skipped.
+func (c *Context) decodeLayer(dir string, conf interface{}) (int, error) {
+	bytes, err := c.loadFile(filepath.Join(dir, c.fileName()))
+	if bytes == nil || err != nil {
+		return 0, err
+	}
+	return len(bytes), c.unmarshal(bytes, conf)
+}
+
+// applyDefaults copies defaults into the value pointed to by conf by
+// encoding and decoding them. If the encoding cannot marshal, e.g. HCL,
+// defaults of the same type as conf are copied by reflection instead.
+func (c *Context) applyDefaults(conf, defaults interface{}) error {
+	if c.Marshal == nil || c.Unmarshal == nil {
+		if copyDefaults(conf, defaults) {
 			return nil
 		}
+		if c.Marshal == nil {
+			return ErrNoMarshal
+		}
+		return ErrNoUnmarshal
+	}
+	bytes, err := c.Marshal(defaults)
+	if err != nil {
+		if copyDefaults(conf, defaults) {
+			return nil
+		}
+		return err
+	}
+	return c.Unmarshal(bytes, conf)
+}
+
+// copyDefaults copies the non-zero values of defaults into the value
+// pointed to by conf. It reports false if their types differ.
+func copyDefaults(conf, defaults interface{}) bool {
+	dst := reflect.ValueOf(conf)
+	if dst.Kind() != reflect.Ptr || dst.IsNil() {
+		return false
+	}
+	src := reflect.Indirect(reflect.ValueOf(defaults))
+	if !src.IsValid() || src.Type() != dst.Elem().Type() {
+		return false
+	}
+	mergeValues(dst.Elem(), src)
+	return true
+}
+
+// Decode reads all data from r and decodes it into the value pointed to
+// by conf, using the encoding of the context.
+func (c *Context) Decode(r io.Reader, conf interface{}) error {
+	bytes, err := ioutil.ReadAll(r)
+	if err != nil {
 		return err
 	}
-	defer f.Close()
-	bytes, err := ioutil.ReadAll(f)
+	return c.unmarshal(bytes, conf)
+}
+
+// unmarshal decodes the contents of a config file into the value pointed
+// to by conf.
+func (c *Context) unmarshal(data []byte, conf interface{}) error {
+	data, err := c.decodeBytes(data)
 	if err != nil {
 		return err
 	}
+	return c.decode(data, conf)
+}
+
+// decode decodes encoded data into the value pointed to by conf, either
+// by its UnmarshalConf method or by the encoding of the context.
+func (c *Context) decode(data []byte, conf interface{}) error {
+	if u, ok := conf.(ConfUnmarshaler); ok {
+		return u.UnmarshalConf(data)
+	}
 	if c.Unmarshal == nil {
 		return ErrNoUnmarshal
 	}
-	return c.Unmarshal(bytes, conf)
+	data = c.stripHeader(c.normalizeRead(c.stripBOM(data)))
+	var err error
+	if len(c.Migrations) > 0 {
+		if data, err = c.migrate(data, conf); err != nil {
+			return err
+		}
+	}
+	return c.Unmarshal(data, conf)
+}
+
+// decodeBytes reverts the transformations of encodeBytes.
+func (c *Context) decodeBytes(data []byte) ([]byte, error) {
+	var err error
+	if c.OnRead != nil {
+		if data, err = c.OnRead(data); err != nil {
+			return nil, err
+		}
+	}
+	if c.EncryptionKey != nil {
+		if data, err = decrypt(c.EncryptionKey, data); err != nil {
+			return nil, err
+		}
+	}
+	if c.Compressed {
+		if data, err = gunzip(data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// Preview returns the encoding of conf as it would be written by Write,
+// without compression or encryption. No file is written.
+func (c *Context) Preview(conf interface{}) ([]byte, error) {
+	return c.encode(conf)
+}
+
+// Equal reports whether a and b have the same encoding, which ignores
+// differences the encoding normalizes, e.g. the order of map keys in JSON.
+func (c *Context) Equal(a, b interface{}) (bool, error) {
+	da, err := c.encode(a)
+	if err != nil {
+		return false, err
+	}
+	db, err := c.encode(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(da, db), nil
+}
+
+// encode encodes conf, either by its MarshalConf method or by the
+// encoding of the context.
+func (c *Context) encode(conf interface{}) ([]byte, error) {
+	if m, ok := conf.(ConfMarshaler); ok {
+		return m.MarshalConf()
+	}
+	if c.Marshal == nil {
+		return nil, ErrNoMarshal
+	}
+	data, err := c.Marshal(conf)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Migrations) > 0 {
+		if data, err = c.stampVersion(data); err != nil {
+			return nil, err
+		}
+	}
+	if c.Header != "" {
+		if data, err = c.addHeader(data); err != nil {
+			return nil, err
+		}
+	}
+	return c.normalizeWrite(data), nil
+}
+
+// marshal encodes conf into the contents of a config file.
+func (c *Context) marshal(conf interface{}) ([]byte, error) {
+	data, err := c.encode(conf)
+	if err != nil {
+		return nil, err
+	}
+	return c.encodeBytes(data)
+}
+
+// encodeBytes compresses, encrypts and transforms encoded data as
+// configured.
+func (c *Context) encodeBytes(data []byte) ([]byte, error) {
+	var err error
+	if c.Compressed {
+		if data, err = gzipBytes(data); err != nil {
+			return nil, err
+		}
+	}
+	if c.EncryptionKey != nil {
+		if data, err = encrypt(c.EncryptionKey, data); err != nil {
+			return nil, err
+		}
+	}
+	if c.OnWrite != nil {
+		return c.OnWrite(data)
+	}
+	return data, nil
 }
 
 // Write writes conf into the config file of the context.
+// The data is written to a temporary file first, which is then renamed
+// over the config file, so readers never see a partially written file.
 func (c *Context) Write(conf interface{}) error {
-	if err := os.MkdirAll(c.Directory, 0777); err != nil {
+	unlock, err := c.lock()
+	if err != nil {
+		return c.wrapErr("write", err)
+	}
+	defer unlock()
+	return c.wrapErr("write", c.write(conf))
+}
+
+func (c *Context) write(conf interface{}) error {
+	c.log("write.start", "path", c.Path())
+	start, size := time.Now(), 0
+	err := c.retry(func() error {
+		bytes, err := c.marshal(conf)
+		if err != nil {
+			return err
+		}
+		size = len(bytes)
+		return c.writeFile(bytes)
+	})
+	c.log("write.done", "path", c.Path(), "bytes", size, "err", err)
+	observe(c.WriteMetrics, start, size, err)
+	return err
+}
+
+// writeFile atomically replaces the config file with bytes.
+func (c *Context) writeFile(bytes []byte) error {
+	return c.writeAtomic(func(w io.Writer) error {
+		_, err := w.Write(bytes)
 		return err
+	})
+}
+
+// fileMode returns the permissions of the config file, 0600 if unset.
+func (c *Context) fileMode() os.FileMode {
+	if c.FileMode == 0 {
+		return 0600
 	}
-	f, err := os.OpenFile(c.Directory + "/" + c.File, os.O_WRONLY | os.O_CREATE, 0666)
+	return c.FileMode
+}
+
+// dirMode returns the permissions of the config directory, 0700 if unset.
+func (c *Context) dirMode() os.FileMode {
+	if c.DirMode == 0 {
+		return 0700
+	}
+	return c.DirMode
+}
+
+// mkdir creates the config directory, unless NoCreateDir is set.
+func (c *Context) mkdir() error {
+	if c.NoCreateDir {
+		return nil
+	}
+	return c.fsys().MkdirAll(c.Directory, c.dirMode())
+}
+
+// tempCounter makes temporary file names unique within the process.
+var tempCounter uint64
+
+// tempPath returns a unique path for a temporary file next to path, so
+// concurrent writes of the same file never share a temporary file.
+func tempPath(path string) string {
+	n := atomic.AddUint64(&tempCounter, 1)
+	return path + ".tmp-" + strconv.Itoa(os.Getpid()) + "-" + strconv.FormatUint(n, 10)
+}
+
+// writeAtomic creates a temporary file, fills it using fn and renames it
+// over the config file.
+func (c *Context) writeAtomic(fn func(w io.Writer) error) error {
+	if c.stdio() {
+		return fn(os.Stdout)
+	}
+	fsys := c.fsys()
+	if err := c.mkdir(); err != nil {
+		return err
+	}
+	path := c.Path()
+	if c.KeepBackup {
+		c.log("write.backup", "path", path)
+		if err := c.backup(); err != nil {
+			return err
+		}
+	}
+	tmp := tempPath(path)
+	f, err := fsys.OpenFile(tmp, os.O_WRONLY | os.O_CREATE | os.O_EXCL, c.fileMode())
 	if err != nil {
 		return err
 	}
-	defer f.Close()
-	if c.Marshal == nil {
-		return ErrNoMarshal
+	w, prepareSum := c.checksumWriter(f, path)
+	if err = fn(w); err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	var sumTmp string
+	if err == nil {
+		sumTmp, err = prepareSum()
+	}
+	if err == nil {
+		c.log("write.rename", "from", tmp, "to", path)
+		err = fsys.Rename(tmp, path)
+	}
+	if err != nil {
+		fsys.Remove(tmp)
+		if sumTmp != "" {
+			fsys.Remove(sumTmp)
+		}
+		return err
+	}
+	return c.commitChecksum(path, sumTmp)
+}
+
+// Reset overwrites the config file with conf, typically a zero value.
+// Unlike Delete, this leaves a valid config file in place.
+func (c *Context) Reset(conf interface{}) error {
+	return c.Write(conf)
+}
+
+// ResetToDefaults overwrites the config file with the defaults of the
+// context. It returns ErrNoDefaults if the context has none.
+func (c *Context) ResetToDefaults() error {
+	if c.Defaults == nil {
+		return ErrNoDefaults
+	}
+	return c.Write(c.Defaults)
+}
+
+// WriteIfChanged writes conf into the config file like Write, but only
+// if its encoding differs from the current contents of the file. It
+// reports whether the file was written.
+func (c *Context) WriteIfChanged(conf interface{}) (bool, error) {
+	unlock, err := c.lock()
+	if err != nil {
+		return false, c.wrapErr("write", err)
+	}
+	defer unlock()
+	written, err := c.writeIfChanged(conf)
+	return written, c.wrapErr("write", err)
+}
+
+func (c *Context) writeIfChanged(conf interface{}) (bool, error) {
+	data, err := c.encode(conf)
+	if err != nil {
+		return false, err
+	}
+	current, err := c.readFile(c.Path())
+	if err == nil {
+		// Compare the decoded contents, as encryption is not deterministic
+		if current, err := c.decodeBytes(current); err == nil && bytes.Equal(current, data) {
+			return false, nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+	if data, err = c.encodeBytes(data); err != nil {
+		return false, err
+	}
+	return true, c.writeFile(data)
+}
+
+// WriteNew writes conf into the config file of the context, but only if
+// the file does not exist yet. Otherwise an error wrapping os.ErrExist
+// is returned.
+func (c *Context) WriteNew(conf interface{}) error {
+	unlock, err := c.lock()
+	if err != nil {
+		return c.wrapErr("write", err)
+	}
+	defer unlock()
+	return c.wrapErr("write", c.writeNew(conf))
+}
+
+func (c *Context) writeNew(conf interface{}) error {
+	bytes, err := c.marshal(conf)
+	if err != nil {
+		return err
+	}
+	if c.stdio() {
+		return c.writeFile(bytes)
+	}
+	fsys := c.fsys()
+	if err := c.mkdir(); err != nil {
+		return err
+	}
+	f, err := fsys.OpenFile(c.Path(), os.O_WRONLY | os.O_CREATE | os.O_EXCL, c.fileMode())
+	if err != nil {
+		return err
+	}
+	w, prepareSum := c.checksumWriter(f, c.Path())
+	if _, err = w.Write(bytes); err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	var sumTmp string
+	if err == nil {
+		sumTmp, err = prepareSum()
+	}
+	if err != nil {
+		fsys.Remove(c.Path())
+		return err
+	}
+	return c.commitChecksum(c.Path(), sumTmp)
+}
+
+// ReadBytes returns the raw contents of the config file, or nil if it
+// does not exist.
+func (c *Context) ReadBytes() ([]byte, error) {
+	unlock, err := c.rlock()
+	if err != nil {
+		return nil, c.wrapErr("read", err)
 	}
-	bytes, err := c.Marshal(conf)
+	defer unlock()
+	bytes, err := c.readFile(c.Path())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return bytes, c.wrapErr("read", err)
+}
+
+// WriteBytes writes the raw bytes into the config file of the context,
+// in the same way as Write.
+func (c *Context) WriteBytes(bytes []byte) error {
+	unlock, err := c.lock()
+	if err != nil {
+		return c.wrapErr("write", err)
+	}
+	defer unlock()
+	return c.wrapErr("write", c.writeFile(bytes))
+}
+
+// Encode encodes conf using the encoding of the context and writes
+// the result to w.
+func (c *Context) Encode(w io.Writer, conf interface{}) error {
+	bytes, err := c.marshal(conf)
 	if err != nil {
 		return err
 	}
-	_, err = f.Write(bytes)
+	_, err = w.Write(bytes)
 	return err
 }
 
+// Exists reports whether the config file of the context exists.
+func (c *Context) Exists() (bool, error) {
+	if c.stdio() {
+		return true, nil
+	}
+	_, err := c.fsys().Stat(c.Path())
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+// ModTime returns the modification time of the config file, or the zero
+// time if it does not exist.
+func (c *Context) ModTime() (time.Time, error) {
+	fi, err := c.fsys().Stat(c.Path())
+	if os.IsNotExist(err) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return fi.ModTime(), nil
+}
+
+// Size returns the size of the config file in bytes, or 0 if it does not
+// exist.
+func (c *Context) Size() (int64, error) {
+	fi, err := c.fsys().Stat(c.Path())
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return fi.Size(), nil
+}
+
+// Touch creates an empty config file if it does not exist, or updates
+// its modification time otherwise.
+func (c *Context) Touch() error {
+	unlock, err := c.lock()
+	if err != nil {
+		return c.wrapErr("touch", err)
+	}
+	defer unlock()
+	return c.wrapErr("touch", c.touch())
+}
+
+func (c *Context) touch() error {
+	if c.stdio() {
+		return nil
+	}
+	fsys := c.fsys()
+	if err := c.mkdir(); err != nil {
+		return err
+	}
+	f, err := fsys.OpenFile(c.Path(), os.O_WRONLY | os.O_CREATE, c.fileMode())
+	if err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	// Only update the modification time if the file system supports it
+	ct, ok := fsys.(interface {
+		Chtimes(name string, atime, mtime time.Time) error
+	})
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	return ct.Chtimes(c.Path(), now, now)
+}
+
+// Delete removes the config file of the context. The directory is left
+// in place. A missing config file is not considered an error.
+func (c *Context) Delete() error {
+	unlock, err := c.lock()
+	if err != nil {
+		return c.wrapErr("delete", err)
+	}
+	defer unlock()
+	if c.stdio() {
+		return c.wrapErr("delete", ErrStdio)
+	}
+	err = c.fsys().Remove(c.Path())
+	if err != nil && !os.IsNotExist(err) {
+		return c.wrapErr("delete", err)
+	}
+	if c.Checksum {
+		err = c.fsys().Remove(checksumPath(c.Path()))
+		if err != nil && !os.IsNotExist(err) {
+			return c.wrapErr("delete", err)
+		}
+	}
+	return nil
+}
+
 // Builder helps create contexts.
 type Builder struct {
 	ctx Context
+	expand bool
+	strict bool
+	sample interface{}
+	fallbacks []Codec
+	json *jsonOptions
+	err error
 }
 
 // Directory sets the directory of the config file.
@@ -102,10 +956,115 @@ func (b *Builder) File(file string) *Builder {
 	return b
 }
 
+// xdgConfigHome returns the user config directory as defined by the
+// XDG Base Directory specification, defaulting to ~/.config.
+func xdgConfigHome() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config")
+}
+
+// ExpandPath enables expansion of a leading ~ to the home directory and
+// of environment variables in the directory of the config file.
+func (b *Builder) ExpandPath() *Builder {
+	b.expand = true
+	return b
+}
+
+// expandPath expands a leading ~ or ~/ to the home directory of the
+// current user and replaces environment variables in dir.
+// The ~user syntax is not supported.
+func expandPath(dir string) string {
+	if dir == "~" || strings.HasPrefix(dir, "~/") || strings.HasPrefix(dir, "~" + string(filepath.Separator)) {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = home + dir[1:]
+		}
+	}
+	return os.ExpandEnv(dir)
+}
+
+// FileMode sets the permissions of the config file, defaults to 0600.
+func (b *Builder) FileMode(mode os.FileMode) *Builder {
+	b.ctx.FileMode = mode
+	return b
+}
+
+// DirMode sets the permissions used when creating the config directory,
+// defaults to 0700.
+func (b *Builder) DirMode(mode os.FileMode) *Builder {
+	b.ctx.DirMode = mode
+	return b
+}
+
+// NoCreateDir disables creating the config directory on Write, so writing
+// fails if the directory does not exist yet.
+func (b *Builder) NoCreateDir() *Builder {
+	b.ctx.NoCreateDir = true
+	return b
+}
+
+// Defaults sets the default values that are applied on Read before the
+// contents of the config file.
+func (b *Builder) Defaults(v interface{}) *Builder {
+	b.ctx.Defaults = v
+	return b
+}
+
+// Validate sets a function that validates the config after reading it.
+// Its error is returned by Read.
+func (b *Builder) Validate(fn func(conf interface{}) error) *Builder {
+	b.ctx.Validator = fn
+	return b
+}
+
+// OnRead sets a function that transforms the raw contents of the config
+// file after reading, before decryption, decompression and decoding.
+func (b *Builder) OnRead(fn func(data []byte) ([]byte, error)) *Builder {
+	b.ctx.OnRead = fn
+	return b
+}
+
+// OnWrite sets a function that transforms the raw contents of the config
+// file before writing, after encoding, compression and encryption.
+func (b *Builder) OnWrite(fn func(data []byte) ([]byte, error)) *Builder {
+	b.ctx.OnWrite = fn
+	return b
+}
+
+// SearchPaths sets the directories that are read by ReadMerged, in order
+// of increasing precedence.
+func (b *Builder) SearchPaths(dirs ...string) *Builder {
+	b.ctx.SearchPaths = dirs
+	return b
+}
+
 // App sets the directory of a config file to the appName in the
-// user config directory, e.g. ~/.config/appName
-func (b *Builder) App(appName string) *Builder {
-	b.ctx.Directory = os.Getenv("XDG_CONFIG_HOME") + "/" + appName
+// user config directory, e.g. ~/.config/appName. Additional path elements
+// are joined after appName, e.g. ~/.config/appName/sub.
+//
+// App strictly follows the XDG layout on every platform. Use UserApp
+// for the platform specific config directory instead. The directory is
+// cleaned, so a trailing slash in XDG_CONFIG_HOME does no harm.
+func (b *Builder) App(appName string, sub ...string) *Builder {
+	elems := append([]string{xdgConfigHome(), appName}, sub...)
+	b.ctx.Directory = filepath.Join(elems...)
+	return b
+}
+
+// UserApp sets the directory of a config file to the appName in the
+// platform specific user config directory, as returned by os.UserConfigDir,
+// e.g. ~/.config/appName on Linux, ~/Library/Application Support/appName
+// on macOS and %AppData%\appName on Windows. Additional path elements
+// are joined after appName.
+func (b *Builder) UserApp(appName string, sub ...string) *Builder {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = xdgConfigHome()
+	}
+	elems := append([]string{dir, appName}, sub...)
+	b.ctx.Directory = filepath.Join(elems...)
 	return b
 }
 
@@ -113,6 +1072,8 @@ func (b *Builder) App(appName string) *Builder {
 func (b *Builder) Marshaller(m MarshalFunc, u UnmarshalFunc) *Builder {
 	b.ctx.Marshal = m
 	b.ctx.Unmarshal = u
+	b.ctx.NewEncoder = nil
+	b.ctx.format = FormatCustom
 	return b
 }
 
@@ -120,22 +1081,111 @@ func jsonMarshalIndent(v interface{}) ([]byte, error) {
 	return json.MarshalIndent(v, "", "    ")
 }
 
+func newJSONEncoder(w io.Writer) StreamEncoder {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	return enc
+}
+
+// jsonOptions configures the output of the JSON encoding.
+type jsonOptions struct {
+	prefix string
+	indent string
+	noHTMLEscape bool
+}
+
+func (o *jsonOptions) marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := o.newEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func (o *jsonOptions) newEncoder(w io.Writer) StreamEncoder {
+	enc := json.NewEncoder(w)
+	enc.SetIndent(o.prefix, o.indent)
+	enc.SetEscapeHTML(!o.noHTMLEscape)
+	return enc
+}
+
+// jsonOptions returns the JSON options of the builder.
+func (b *Builder) jsonOptions() *jsonOptions {
+	if b.json == nil {
+		b.json = &jsonOptions{indent: "    "}
+	}
+	return b.json
+}
+
+// Compact disables indentation of the JSON encoding, so that the config
+// is written on a single line.
+func (b *Builder) Compact() *Builder {
+	o := b.jsonOptions()
+	o.prefix, o.indent = "", ""
+	return b
+}
+
+// Indent sets the prefix and indentation of the JSON encoding, like
+// json.MarshalIndent. The default is an indentation of four spaces.
+func (b *Builder) Indent(prefix, indent string) *Builder {
+	o := b.jsonOptions()
+	o.prefix, o.indent = prefix, indent
+	return b
+}
+
+// NoHTMLEscape disables escaping of <, > and & in JSON strings, which
+// keeps URLs and templates readable in the config file.
+func (b *Builder) NoHTMLEscape() *Builder {
+	b.jsonOptions().noHTMLEscape = true
+	return b
+}
+
 // JSON sets the encoding to the JSON format.
 func (b *Builder) JSON() *Builder {
 	if b.ctx.File == "" {
 		b.ctx.File = "config.json"
 	}
-	return b.Marshaller(jsonMarshalIndent, json.Unmarshal)
+	b.codec(FormatJSON, jsonMarshalIndent, json.Unmarshal)
+	return b.StreamEncoder(newJSONEncoder)
 }
 
-// Create creates the context.
+// Create creates the context. If no encoding was set, it is inferred
+// from the extension of the file name, e.g. ".json" or ".yaml".
 func (b *Builder) Create() *Context {
+	if b.ctx.Marshal == nil && b.ctx.Unmarshal == nil {
+		if fn, ok := formatByExt(b.ctx.File); ok {
+			fn(b)
+		}
+	}
+	if b.json != nil && (b.ctx.format == FormatJSON || b.ctx.format == FormatJSONC) {
+		b.ctx.Marshal = b.json.marshal
+		b.ctx.NewEncoder = b.json.newEncoder
+	}
+	if u, ok := strictUnmarshal[b.ctx.format]; ok && b.strict {
+		b.ctx.Unmarshal = u
+	}
+	if len(b.fallbacks) > 0 {
+		b.ctx.Unmarshal = fallbackUnmarshal(b.ctx.Unmarshal, b.fallbacks)
+	}
+	if b.expand {
+		b.ctx.Directory = expandPath(b.ctx.Directory)
+	}
 	if b.ctx.Directory == "" {
 		b.ctx.Directory = "."
 	}
+	b.ctx.Directory = filepath.Clean(b.ctx.Directory)
 	if b.ctx.File == "" {
 		b.ctx.File = "config"
 	}
+	if b.ctx.Compressed && !strings.HasSuffix(b.ctx.File, ".gz") {
+		b.ctx.File += ".gz"
+	}
+	if b.ctx.FileMode == 0 {
+		b.ctx.FileMode = 0600
+	}
+	if b.ctx.DirMode == 0 {
+		b.ctx.DirMode = 0700
+	}
 	return &b.ctx
 }
 
@@ -143,3 +1193,12 @@ func (b *Builder) Create() *Context {
 func Build() *Builder {
 	return &Builder{}
 }
+
+// DefaultApp returns a context for the JSON file "config.json" in the
+// user config directory of appName, that is only accessible by the
+// current user. It is a shortcut for
+//
+//    Build().UserApp(appName).JSON().FileMode(0600).Create()
+func DefaultApp(appName string) *Context {
+	return Build().UserApp(appName).JSON().FileMode(0600).Create()
+}
@@ -1,7 +1,17 @@
 package conf
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 type TestConfig struct {
@@ -61,3 +71,1047 @@ func TestGlobal(t *testing.T) {
 		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
 	}
 }
+
+func TestWriteAtomic(t *testing.T) {
+	dir := t.TempDir()
+	conf := Build().Directory(dir).JSON().Create()
+
+	// Overwrite an existing config
+	if err := conf.Write(TestConfig{String: "first"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := conf.Write(TestConfig{String: "second"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// No temporary files should be left behind
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Name() != "config.json" {
+		t.Errorf("Unexpected files in config directory: %v", files)
+	}
+
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead.String != "second" {
+		t.Errorf("Unexpected config: %v", cfgRead)
+	}
+}
+
+func TestWriteShorter(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+
+	// Write a long config, then overwrite it with a shorter one
+	long := map[string]interface{}{"a": 1, "b": strings.Repeat("x", 1024)}
+	if err := conf.Write(long); err != nil {
+		t.Fatal(err)
+	}
+	short := map[string]interface{}{"a": 1}
+	if err := conf.Write(short); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfgRead map[string]interface{}
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfgRead) != 1 || cfgRead["a"] != 1.0 {
+		t.Errorf("Unexpected config: %v", cfgRead)
+	}
+}
+
+func TestExists(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+
+	exists, err := conf.Exists()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exists {
+		t.Error("Config should not exist yet")
+	}
+
+	if err := conf.Write(TestConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	exists, err = conf.Exists()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists {
+		t.Error("Config should exist after writing")
+	}
+}
+
+func TestDirectoryTrailingSlash(t *testing.T) {
+	dir := t.TempDir()
+	conf := Build().Directory(dir + "/").JSON().Create()
+
+	if err := conf.Write(TestConfig{String: "test"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "config.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead.String != "test" {
+		t.Errorf("Unexpected config: %v", cfgRead)
+	}
+}
+
+func TestAppXDGConfigHome(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+	conf := Build().App("goconftest").Create()
+	if conf.Directory != filepath.Join("/tmp/xdg", "goconftest") {
+		t.Errorf("Unexpected directory: %s", conf.Directory)
+	}
+}
+
+func TestAppXDGConfigHomeUnset(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("HOME", "/home/test")
+	conf := Build().App("goconftest").Create()
+	if conf.Directory != filepath.Join("/home/test", ".config", "goconftest") {
+		t.Errorf("Unexpected directory: %s", conf.Directory)
+	}
+}
+
+func TestUserApp(t *testing.T) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		t.Skip(err)
+	}
+	conf := Build().UserApp("goconftest").Create()
+	if conf.Directory != filepath.Join(dir, "goconftest") {
+		t.Errorf("Unexpected directory: %s", conf.Directory)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	conf := Build().JSON().Create()
+
+	var cfgRead TestConfig
+	r := strings.NewReader(`{"String": "test", "Number": 5}`)
+	if err := conf.Decode(r, &cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead.String != "test" || cfgRead.Number != 5 {
+		t.Errorf("Unexpected config: %v", cfgRead)
+	}
+
+	conf = Build().Create()
+	if err := conf.Decode(r, &cfgRead); err != ErrNoUnmarshal {
+		t.Errorf("Expected ErrNoUnmarshal, got %v", err)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	conf := Build().JSON().Create()
+
+	// Encode into a buffer and decode it again
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	var buf bytes.Buffer
+	if err := conf.Encode(&buf, cfg); err != nil {
+		t.Fatal(err)
+	}
+	var cfgRead TestConfig
+	if err := conf.Decode(&buf, &cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfg != cfgRead {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+
+	conf = Build().Create()
+	if err := conf.Encode(&buf, cfg); err != ErrNoMarshal {
+		t.Errorf("Expected ErrNoMarshal, got %v", err)
+	}
+}
+
+func TestFileMode(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "sub")
+	conf := Build().Directory(dir).JSON().Create()
+	if err := conf.Write(TestConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check default permissions
+	fi, err := os.Stat(conf.Directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0700 {
+		t.Errorf("Unexpected directory permissions: %v", perm)
+	}
+	fi, err = os.Stat(filepath.Join(conf.Directory, conf.File))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0600 {
+		t.Errorf("Unexpected file permissions: %v", perm)
+	}
+
+	// Check custom permissions
+	conf = Build().Directory(dir).File("custom.json").FileMode(0640).JSON().Create()
+	if err := conf.Write(TestConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	fi, err = os.Stat(filepath.Join(conf.Directory, conf.File))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0640 {
+		t.Errorf("Unexpected file permissions: %v", perm)
+	}
+}
+
+func TestContextLiteral(t *testing.T) {
+	// Initialize config context without the builder
+	conf := &Context{
+		Directory: filepath.Join(t.TempDir(), "sub"),
+		File:      "config.json",
+		Marshal:   json.Marshal,
+		Unmarshal: json.Unmarshal,
+	}
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfg != cfgRead {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+
+	// Check default permissions
+	fi, err := os.Stat(conf.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0600 {
+		t.Errorf("Unexpected file permissions: %v", perm)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+	if err := conf.Write(TestConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conf.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	exists, err := conf.Exists()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exists {
+		t.Error("Config should not exist after deleting")
+	}
+
+	// Deleting a missing config is fine
+	if err := conf.Delete(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	dir := t.TempDir()
+	defaults := TestConfig{"default", 1, struct{ Field string }{"sub"}}
+	conf := Build().Directory(dir).JSON().Defaults(defaults).Create()
+
+	// Config file only sets a single field
+	err := ioutil.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"Number": 5}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	expected := TestConfig{"default", 5, struct{ Field string }{"sub"}}
+	if cfgRead != expected {
+		t.Errorf("Configs differ: %v, %v", expected, cfgRead)
+	}
+}
+
+func TestReadMerged(t *testing.T) {
+	system, user, missing := t.TempDir(), t.TempDir(), t.TempDir()
+	var sizes []int
+	conf := Build().Directory(user).SearchPaths(system, missing, user).JSON().Metrics(
+		func(dur time.Duration, size int, err error) {
+			sizes = append(sizes, size)
+		}, nil,
+	).Create()
+
+	err := ioutil.WriteFile(filepath.Join(system, "config.json"), []byte(`{"String": "system", "Number": 1}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(user, "config.json"), []byte(`{"Number": 2}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfgRead TestConfig
+	if err := conf.ReadMerged(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead.String != "system" || cfgRead.Number != 2 {
+		t.Errorf("Unexpected config: %v", cfgRead)
+	}
+	if len(sizes) != 1 || sizes[0] != len(`{"String": "system", "Number": 1}`)+len(`{"Number": 2}`) {
+		t.Errorf("Unexpected read metrics: %v", sizes)
+	}
+
+	// Errors are wrapped like those of Read
+	err = ioutil.WriteFile(filepath.Join(user, "config.json"), []byte(`{`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cerr *ConfigError
+	if err := conf.ReadMerged(&cfgRead); !errors.As(err, &cerr) || cerr.Op != "read" {
+		t.Errorf("Expected read ConfigError, got %v", err)
+	}
+}
+
+func TestConcurrentReadWrite(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+	if err := conf.Write(TestConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			errs <- conf.Write(TestConfig{String: strings.Repeat("x", i), Number: i})
+		}(i)
+		go func() {
+			defer wg.Done()
+			var cfgRead TestConfig
+			errs <- conf.Read(&cfgRead)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfgRead.String) != cfgRead.Number {
+		t.Errorf("Inconsistent config: %v", cfgRead)
+	}
+}
+
+func TestConcurrentContexts(t *testing.T) {
+	// Initialize two contexts for the same file without file locking
+	dir := t.TempDir()
+	confs := []*Context{
+		Build().Directory(dir).JSON().Create(),
+		Build().Directory(dir).JSON().Create(),
+	}
+	if err := confs[0].Write(TestConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 200)
+	for i := 0; i < 50; i++ {
+		for _, conf := range confs {
+			wg.Add(2)
+			go func(conf *Context, i int) {
+				defer wg.Done()
+				errs <- conf.Write(TestConfig{String: strings.Repeat("x", i), Number: i})
+			}(conf, i)
+			go func(conf *Context) {
+				defer wg.Done()
+				var cfgRead TestConfig
+				errs <- conf.Read(&cfgRead)
+			}(conf)
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var cfgRead TestConfig
+	if err := confs[1].Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfgRead.String) != cfgRead.Number {
+		t.Errorf("Inconsistent config: %v", cfgRead)
+	}
+}
+
+func TestConfigError(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).Create()
+
+	err := conf.Write(TestConfig{})
+	var cerr *ConfigError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("Expected ConfigError, got %v", err)
+	}
+	if cerr.Op != "write" || cerr.Path != filepath.Join(conf.Directory, conf.File) {
+		t.Errorf("Unexpected error: %v", cerr)
+	}
+	if !errors.Is(err, ErrNoMarshal) {
+		t.Errorf("Expected ErrNoMarshal, got %v", err)
+	}
+}
+
+func TestReadMissing(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+
+	// A missing config file is not an error
+	cfgRead := TestConfig{String: "unchanged"}
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead.String != "unchanged" {
+		t.Errorf("Unexpected config: %v", cfgRead)
+	}
+
+	// Other errors are returned
+	file := filepath.Join(t.TempDir(), "file")
+	if err := ioutil.WriteFile(file, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	conf = Build().Directory(file).JSON().Create()
+	if err := conf.Read(&cfgRead); err == nil {
+		t.Error("Expected error when directory is a file")
+	}
+}
+
+func TestPath(t *testing.T) {
+	conf := Build().Directory("dir/").File("config.json").Create()
+	if conf.Path() != filepath.Join("dir", "config.json") {
+		t.Errorf("Unexpected path: %s", conf.Path())
+	}
+}
+
+
+func TestExpandPath(t *testing.T) {
+	t.Setenv("HOME", "/home/test")
+	t.Setenv("GOCONFTEST_DIR", "/tmp/goconftest")
+
+	tests := map[string]string{
+		"~":                   "/home/test",
+		"~/":                  "/home/test",
+		"~/configs":           "/home/test/configs",
+		"~user/configs":       "~user/configs",
+		"$GOCONFTEST_DIR/sub": "/tmp/goconftest/sub",
+		"/etc/~":              "/etc/~",
+	}
+	for dir, expected := range tests {
+		conf := Build().Directory(dir).ExpandPath().Create()
+		if conf.Directory != expected {
+			t.Errorf("Expanding %q: expected %q, got %q", dir, expected, conf.Directory)
+		}
+	}
+
+	// Paths are used literally without ExpandPath
+	conf := Build().Directory("~/configs").Create()
+	if conf.Directory != "~/configs" {
+		t.Errorf("Unexpected directory: %s", conf.Directory)
+	}
+}
+
+func TestReadWriteBytes(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+
+	data, err := conf.ReadBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data != nil {
+		t.Errorf("Expected nil for missing config, got %q", data)
+	}
+
+	expected := []byte("raw\x00bytes")
+	if err := conf.WriteBytes(expected); err != nil {
+		t.Fatal(err)
+	}
+	data, err = conf.ReadBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, expected) {
+		t.Errorf("Bytes differ: %q, %q", expected, data)
+	}
+}
+
+func TestDefaultApp(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	conf := DefaultApp("goconftest")
+
+	expected := Build().UserApp("goconftest").Create()
+	if conf.Path() != filepath.Join(expected.Directory, "config.json") {
+		t.Errorf("Unexpected path: %s", conf.Path())
+	}
+	if conf.FileMode != 0600 {
+		t.Errorf("Unexpected file mode: %v", conf.FileMode)
+	}
+
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfg != cfgRead {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+}
+
+func TestClone(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+
+	clone := conf.Clone()
+	clone.Directory = "other"
+	if conf.Directory == "other" {
+		t.Error("Clone is not independent")
+	}
+
+	secrets := conf.WithFile("secrets.json")
+	if secrets.File != "secrets.json" || conf.File != "config.json" {
+		t.Errorf("Unexpected files: %s, %s", conf.File, secrets.File)
+	}
+	if secrets.Directory != conf.Directory {
+		t.Errorf("Unexpected directory: %s", secrets.Directory)
+	}
+
+	// The clone keeps the encoding
+	if err := secrets.Write(TestConfig{String: "secret"}); err != nil {
+		t.Fatal(err)
+	}
+	var cfgRead TestConfig
+	if err := secrets.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead.String != "secret" {
+		t.Errorf("Unexpected config: %v", cfgRead)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	errNegative := errors.New("Number must not be negative")
+	conf := Build().Directory(t.TempDir()).JSON().Validate(func(conf interface{}) error {
+		if conf.(*TestConfig).Number < 0 {
+			return errNegative
+		}
+		return nil
+	}).Create()
+
+	var cfgRead TestConfig
+	if err := conf.Write(TestConfig{Number: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conf.Write(TestConfig{Number: -1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := conf.Read(&cfgRead); !errors.Is(err, errNegative) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+}
+
+func TestWriteNew(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+
+	if err := conf.WriteNew(TestConfig{String: "first"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := conf.WriteNew(TestConfig{String: "second"}); !errors.Is(err, os.ErrExist) {
+		t.Errorf("Expected os.ErrExist, got %v", err)
+	}
+
+	// The existing config is untouched
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead.String != "first" {
+		t.Errorf("Unexpected config: %v", cfgRead)
+	}
+}
+
+func TestPreview(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	preview, err := conf.Preview(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exists, _ := conf.Exists(); exists {
+		t.Error("Preview should not write the config")
+	}
+
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+	data, err := conf.ReadBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(preview, data) {
+		t.Errorf("Preview differs from written config: %s, %s", preview, data)
+	}
+
+	conf = Build().Create()
+	if _, err := conf.Preview(cfg); err != ErrNoMarshal {
+		t.Errorf("Expected ErrNoMarshal, got %v", err)
+	}
+}
+
+func TestModTime(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+
+	mtime, err := conf.ModTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mtime.IsZero() {
+		t.Errorf("Expected zero time for missing config, got %v", mtime)
+	}
+
+	if err := conf.Write(TestConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	mtime, err = conf.ModTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(conf.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mtime.Equal(fi.ModTime()) {
+		t.Errorf("Unexpected modification time: %v, %v", fi.ModTime(), mtime)
+	}
+}
+
+func TestTouch(t *testing.T) {
+	conf := Build().Directory(filepath.Join(t.TempDir(), "sub")).JSON().Create()
+
+	if err := conf.Touch(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := conf.ReadBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data == nil || len(data) != 0 {
+		t.Errorf("Expected empty config, got %q", data)
+	}
+
+	// Touching an existing config keeps its contents
+	if err := conf.Write(TestConfig{String: "test"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := conf.Touch(); err != nil {
+		t.Fatal(err)
+	}
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead.String != "test" {
+		t.Errorf("Unexpected config: %v", cfgRead)
+	}
+}
+
+func TestReset(t *testing.T) {
+	defaults := TestConfig{String: "default"}
+	conf := Build().Directory(t.TempDir()).JSON().Defaults(defaults).Create()
+	if err := conf.Write(TestConfig{"Just testing", 123, struct{ Field string }{"test"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfgRead TestConfig
+	if err := conf.Reset(TestConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead != (TestConfig{}) {
+		t.Errorf("Unexpected config after reset: %v", cfgRead)
+	}
+
+	if err := conf.ResetToDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	cfgRead = TestConfig{}
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead != defaults {
+		t.Errorf("Unexpected config after reset: %v", cfgRead)
+	}
+
+	conf = Build().Directory(t.TempDir()).JSON().Create()
+	if err := conf.ResetToDefaults(); err != ErrNoDefaults {
+		t.Errorf("Expected ErrNoDefaults, got %v", err)
+	}
+}
+
+func TestReadWriteHooks(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().OnWrite(func(data []byte) ([]byte, error) {
+		return []byte(base64.StdEncoding.EncodeToString(data)), nil
+	}).OnRead(func(data []byte) ([]byte, error) {
+		return base64.StdEncoding.DecodeString(string(data))
+	}).Create()
+
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	// The file is base64 encoded
+	data, err := conf.ReadBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := base64.StdEncoding.DecodeString(string(data)); err != nil {
+		t.Errorf("Config is not base64 encoded: %s", data)
+	}
+
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfg != cfgRead {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+}
+
+func TestReadOrDefault(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+	defaults := TestConfig{"default", 1, struct{ Field string }{"sub"}}
+
+	// Missing config
+	var cfgRead TestConfig
+	if err := conf.ReadOrDefault(&cfgRead, defaults); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead != defaults {
+		t.Errorf("Configs differ: %v, %v", defaults, cfgRead)
+	}
+
+	// Present config
+	if err := ioutil.WriteFile(conf.Path(), []byte(`{"Number": 5}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfgRead = TestConfig{}
+	if err := conf.ReadOrDefault(&cfgRead, defaults); err != nil {
+		t.Fatal(err)
+	}
+	expected := TestConfig{"default", 5, struct{ Field string }{"sub"}}
+	if cfgRead != expected {
+		t.Errorf("Configs differ: %v, %v", expected, cfgRead)
+	}
+}
+
+func TestAppSubdir(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+	conf := Build().App("goconftest", "sub", "dir").Create()
+	if conf.Directory != filepath.Join("/tmp/xdg", "goconftest", "sub", "dir") {
+		t.Errorf("Unexpected directory: %s", conf.Directory)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("HOME", "/home/test")
+	conf = Build().App("goconftest", "sub").Create()
+	if conf.Directory != filepath.Join("/home/test", ".config", "goconftest", "sub") {
+		t.Errorf("Unexpected directory: %s", conf.Directory)
+	}
+}
+
+func TestWriteIfChanged(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	for _, b := range []*Builder{Build().JSON(), Build().JSON().Compressed().Encrypted(key)} {
+		conf := b.Directory(t.TempDir()).Create()
+		cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+
+		written, err := conf.WriteIfChanged(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !written {
+			t.Error("Expected write of a new config")
+		}
+
+		// Set an old mtime to detect writes
+		old := time.Now().Add(-time.Hour).Truncate(time.Second)
+		if err := os.Chtimes(conf.Path(), old, old); err != nil {
+			t.Fatal(err)
+		}
+		written, err = conf.WriteIfChanged(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if written {
+			t.Error("Unexpected write of an unchanged config")
+		}
+		if mtime, _ := conf.ModTime(); !mtime.Equal(old) {
+			t.Errorf("Modification time changed: %v", mtime)
+		}
+
+		cfg.Number = 5
+		written, err = conf.WriteIfChanged(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !written {
+			t.Error("Expected write of a changed config")
+		}
+	}
+}
+
+func TestCompact(t *testing.T) {
+	// The order of Compact and JSON does not matter
+	for _, b := range []*Builder{Build().Compact().JSON(), Build().JSON().Compact()} {
+		conf := b.Create()
+		cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+		data, err := conf.Preview(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(data, []byte("\n")) {
+			t.Errorf("Config is not compact: %s", data)
+		}
+		var buf bytes.Buffer
+		if err := conf.NewEncoder(&buf).Encode(cfg); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Count(buf.String(), "\n") != 1 {
+			t.Errorf("Stream encoding is not compact: %s", buf.Bytes())
+		}
+	}
+}
+
+func TestIndent(t *testing.T) {
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	for _, indent := range []string{"  ", "\t"} {
+		conf := Build().JSON().Indent("", indent).Create()
+		data, err := conf.Preview(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, _ := json.MarshalIndent(cfg, "", indent)
+		if !bytes.Equal(data, expected) {
+			t.Errorf("Unexpected indentation:\n%s", data)
+		}
+	}
+}
+
+func TestNoHTMLEscape(t *testing.T) {
+	cfg := TestConfig{String: "<a href=\"?a=1&b=2\">"}
+
+	conf := Build().JSON().Create()
+	data, err := conf.Preview(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("<a")) {
+		t.Errorf("Expected escaped HTML by default: %s", data)
+	}
+
+	conf = Build().JSON().NoHTMLEscape().Create()
+	data, err = conf.Preview(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(data, []byte("<a href=\\\"?a=1&b=2\\\">")) {
+		t.Errorf("Expected literal HTML: %s", data)
+	}
+}
+
+// lineConfig encodes itself as "key=value" lines.
+type lineConfig struct {
+	Name string
+	Port string
+}
+
+func (l *lineConfig) MarshalConf() ([]byte, error) {
+	return []byte("name=" + l.Name + "\nport=" + l.Port + "\n"), nil
+}
+
+func (l *lineConfig) UnmarshalConf(data []byte) error {
+	for _, line := range strings.Split(string(data), "\n") {
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "name":
+			l.Name = kv[1]
+		case "port":
+			l.Port = kv[1]
+		}
+	}
+	return nil
+}
+
+func TestConfMarshaler(t *testing.T) {
+	// Initialize config context without any encoding
+	conf := Build().Directory(t.TempDir()).File("config.txt").Create()
+
+	cfg := &lineConfig{"Just testing", "8080"}
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+	data, err := conf.ReadBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "name=Just testing\nport=8080\n" {
+		t.Errorf("Unexpected file contents: %q", data)
+	}
+
+	var cfgRead lineConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead != *cfg {
+		t.Errorf("Configs differ: %v, %v", cfgRead, *cfg)
+	}
+}
+
+func TestNoCreateDir(t *testing.T) {
+	// Initialize config context in a missing directory
+	dir := filepath.Join(t.TempDir(), "missing")
+	conf := Build().Directory(dir).JSON().NoCreateDir().Create()
+
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if err := conf.Write(cfg); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected ErrNotExist, got %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Expected directory not to be created: %v", err)
+	}
+
+	// An existing directory is written to
+	if err := os.Mkdir(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	conf := Build().JSON().Create()
+
+	// Maps filled in different order
+	a := map[string]TestConfig{}
+	a["one"] = TestConfig{String: "one"}
+	a["two"] = TestConfig{String: "two"}
+	b := map[string]TestConfig{}
+	b["two"] = TestConfig{String: "two"}
+	b["one"] = TestConfig{String: "one"}
+
+	equal, err := conf.Equal(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equal {
+		t.Error("Expected configs to be equal")
+	}
+
+	b["two"] = TestConfig{String: "three"}
+	if equal, err = conf.Equal(a, b); err != nil || equal {
+		t.Errorf("Expected configs to differ: %v", err)
+	}
+
+	conf = Build().Create()
+	if _, err := conf.Equal(a, b); err != ErrNoMarshal {
+		t.Errorf("Expected ErrNoMarshal, got %v", err)
+	}
+}
+
+func TestCleanDirectory(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home+"/")
+	conf := Build().App("myapp").JSON().Create()
+	if conf.Directory != filepath.Join(home, "myapp") {
+		t.Errorf("Unexpected directory: %s", conf.Directory)
+	}
+
+	conf = Build().Directory("a//b/../c/").JSON().Create()
+	if conf.Directory != filepath.Join("a", "c") {
+		t.Errorf("Unexpected directory: %s", conf.Directory)
+	}
+}
+
+func TestSize(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+	size, err := conf.Size()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != 0 {
+		t.Errorf("Expected size 0 for missing file, got %d", size)
+	}
+
+	if err := conf.WriteBytes([]byte(`{"String": "test"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if size, err = conf.Size(); err != nil {
+		t.Fatal(err)
+	}
+	if size != 18 {
+		t.Errorf("Expected size 18, got %d", size)
+	}
+}
+
+func BenchmarkRead(b *testing.B) {
+	conf := Build().Directory(b.TempDir()).JSON().Create()
+	large := make([]TestConfig, 10000)
+	for i := range large {
+		large[i] = TestConfig{"Just testing", i, struct{ Field string }{"test"}}
+	}
+	if err := conf.Write(large); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var cfgRead []TestConfig
+		if err := conf.Read(&cfgRead); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
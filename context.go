@@ -0,0 +1,55 @@
+package conf
+
+import (
+	"context"
+)
+
+// lockContext acquires a lock using lockFn, unless ctx is done first.
+func lockContext(ctx context.Context, lockFn func() (func(), error)) (unlock func(), err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	type result struct {
+		unlock func()
+		err    error
+	}
+	locked := make(chan result, 1)
+	go func() {
+		unlock, err := lockFn()
+		locked <- result{unlock, err}
+	}()
+	select {
+	case r := <-locked:
+		return r.unlock, r.err
+	case <-ctx.Done():
+		// Release the lock as soon as it is acquired
+		go func() {
+			if r := <-locked; r.err == nil {
+				r.unlock()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
+// ReadContext reads the config file like Read, but gives up waiting for
+// locks when ctx is done.
+func (c *Context) ReadContext(ctx context.Context, conf interface{}) error {
+	unlock, err := lockContext(ctx, c.rlock)
+	if err != nil {
+		return c.wrapErr("read", err)
+	}
+	defer unlock()
+	return c.wrapErr("read", c.read(conf))
+}
+
+// WriteContext writes the config file like Write, but gives up waiting
+// for locks when ctx is done.
+func (c *Context) WriteContext(ctx context.Context, conf interface{}) error {
+	unlock, err := lockContext(ctx, c.lock)
+	if err != nil {
+		return c.wrapErr("write", err)
+	}
+	defer unlock()
+	return c.wrapErr("write", c.write(conf))
+}
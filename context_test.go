@@ -0,0 +1,60 @@
+package conf
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReadURLContextCancelled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"String": "remote"}`))
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	conf := Build().JSON().Create()
+	var cfgRead TestConfig
+	if err := conf.ReadURLContext(ctx, srv.URL, &cfgRead); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestReadWriteContext(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+	ctx := context.Background()
+
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if err := conf.WriteContext(ctx, cfg); err != nil {
+		t.Fatal(err)
+	}
+	var cfgRead TestConfig
+	if err := conf.ReadContext(ctx, &cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfg != cfgRead {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+
+	// Give up waiting for a held lock
+	unlock, err := conf.lock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := conf.ReadContext(ctx, &cfgRead); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	unlock()
+
+	// The abandoned lock is released again
+	if err := conf.WriteContext(context.Background(), cfg); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,30 @@
+package conf
+
+// Convert reads the config file of c into the value pointed to by conf and
+// writes it through the context to, transcoding the config file from the
+// format of c to the format of to, e.g. from JSON to YAML:
+//
+//	from := conf.Build().App("myapp").JSON().Create()
+//	to := conf.Build().App("myapp").YAML().Create()
+//	err := from.Convert(to, &cfg)
+//
+// The config file of c is left in place; use Move to remove it.
+func (c *Context) Convert(to *Context, conf interface{}) error {
+	if err := c.Read(conf); err != nil {
+		return err
+	}
+	return to.Write(conf)
+}
+
+// Move converts the config file of c like Convert and deletes it once it
+// has been written through the context to. If both contexts use the same
+// path, the file is not deleted.
+func (c *Context) Move(to *Context, conf interface{}) error {
+	if err := c.Convert(to, conf); err != nil {
+		return err
+	}
+	if c.Path() == to.Path() {
+		return nil
+	}
+	return c.Delete()
+}
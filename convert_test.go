@@ -0,0 +1,56 @@
+package conf
+
+import (
+	"os"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	// Initialize config contexts
+	from := Build().Directory("testdata").JSON().Create()
+	to := Build().Directory(t.TempDir()).YAML().Create()
+
+	var cfg TestConfig
+	if err := from.Convert(to, &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfgRead TestConfig
+	if err := to.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	expected := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if cfgRead != expected {
+		t.Errorf("Configs differ: %v, %v", expected, cfgRead)
+	}
+	if ok, err := from.Exists(); err != nil || !ok {
+		t.Errorf("Expected source file to be kept: %v", err)
+	}
+}
+
+func TestMove(t *testing.T) {
+	// Initialize config contexts
+	dir := t.TempDir()
+	from := Build().Directory(dir).JSON().Create()
+	to := Build().Directory(dir).YAML().Create()
+
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if err := from.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+	var cfgMoved TestConfig
+	if err := from.Move(to, &cfgMoved); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(from.Path()); !os.IsNotExist(err) {
+		t.Errorf("Expected source file to be deleted: %v", err)
+	}
+
+	var cfgRead TestConfig
+	if err := to.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead != cfg {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+}
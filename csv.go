@@ -0,0 +1,110 @@
+package conf
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var ErrNotSlice = errors.New("Config is not a slice of structs")
+
+// csvFields returns the keys and field indices of a flat struct type.
+func csvFields(t reflect.Type) (keys []string, indices []int) {
+	for i := 0; i < t.NumField(); i++ {
+		if key, ok := fieldKey(t.Field(i)); ok {
+			keys = append(keys, key)
+			indices = append(indices, i)
+		}
+	}
+	return keys, indices
+}
+
+func csvMarshal(v interface{}) ([]byte, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() != reflect.Struct {
+		return nil, ErrNotSlice
+	}
+	keys, indices := csvFields(rv.Type().Elem())
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(keys); err != nil {
+		return nil, err
+	}
+	record := make([]string, len(keys))
+	for i := 0; i < rv.Len(); i++ {
+		for j, index := range indices {
+			s, err := formatValue(rv.Index(i).Field(index))
+			if err != nil {
+				return nil, fmt.Errorf("csv: %s: %v", keys[j], err)
+			}
+			record[j] = s
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func csvUnmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice || rv.Elem().Type().Elem().Kind() != reflect.Struct {
+		return ErrNotSlice
+	}
+	rv = rv.Elem()
+	elemType := rv.Type().Elem()
+	keys, indices := csvFields(elemType)
+
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return errors.New("csv: missing header")
+	}
+
+	// Map the columns of the header to the struct fields
+	fields := make(map[string]int)
+	for i, key := range keys {
+		fields[key] = indices[i]
+	}
+	header := records[0]
+	if len(header) != len(keys) {
+		return fmt.Errorf("csv: header has %d columns, expected %d", len(header), len(keys))
+	}
+	columns := make([]int, len(header))
+	for i, key := range header {
+		index, ok := fields[key]
+		if !ok {
+			return fmt.Errorf("csv: unknown column %q", key)
+		}
+		columns[i] = index
+	}
+
+	slice := reflect.MakeSlice(rv.Type(), 0, len(records)-1)
+	for n, record := range records[1:] {
+		elem := reflect.New(elemType).Elem()
+		for i, s := range record {
+			if err := setValue(elem.Field(columns[i]), s); err != nil {
+				return fmt.Errorf("csv: line %d: %s: %v", n+2, header[i], err)
+			}
+		}
+		slice = reflect.Append(slice, elem)
+	}
+	rv.Set(slice)
+	return nil
+}
+
+// CSV sets the encoding to CSV, for configs that are slices of flat
+// structs. The header row consists of the field keys, which are the field
+// names or the names given in their conf tags.
+func (b *Builder) CSV() *Builder {
+	if b.ctx.File == "" {
+		b.ctx.File = "config.csv"
+	}
+	return b.codec(FormatCSV, csvMarshal, csvUnmarshal)
+}
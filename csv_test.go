@@ -0,0 +1,74 @@
+package conf
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+type Route struct {
+	Path    string `conf:"path"`
+	Backend string `conf:"backend"`
+	Weight  int    `conf:"weight"`
+}
+
+func TestCSV(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).CSV().Create()
+	if conf.File != "config.csv" {
+		t.Errorf("Unexpected file name: %s", conf.File)
+	}
+
+	routes := []Route{
+		{"/", "frontend, primary", 10},
+		{"/api", "api", 1},
+	}
+	if err := conf.Write(routes); err != nil {
+		t.Fatal(err)
+	}
+
+	var routesRead []Route
+	if err := conf.Read(&routesRead); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(routes, routesRead) {
+		t.Errorf("Configs differ: %v, %v", routes, routesRead)
+	}
+}
+
+func TestCSVHeader(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).CSV().Create()
+
+	// Columns may be reordered
+	data := "weight,path,backend\n5,/,frontend\n"
+	if err := ioutil.WriteFile(conf.Path(), []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	var routesRead []Route
+	if err := conf.Read(&routesRead); err != nil {
+		t.Fatal(err)
+	}
+	if len(routesRead) != 1 || routesRead[0] != (Route{"/", "frontend", 5}) {
+		t.Errorf("Unexpected config: %v", routesRead)
+	}
+
+	// Unknown columns are rejected
+	data = "path,backend,unknown\n/,frontend,1\n"
+	if err := ioutil.WriteFile(conf.Path(), []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := conf.Read(&routesRead); err == nil {
+		t.Error("Expected error for mismatching header")
+	}
+}
+
+func TestCSVUnsupported(t *testing.T) {
+	// Nested structs cannot be read back, so they are not written
+	conf := Build().Directory(t.TempDir()).CSV().Create()
+	cfg := []TestConfig{{"Just testing", 123, struct{ Field string }{"test"}}}
+	if err := conf.Write(cfg); err == nil {
+		t.Error("Expected error for nested struct")
+	}
+	if exists, _ := conf.Exists(); exists {
+		t.Error("Expected no config file")
+	}
+}
@@ -0,0 +1,70 @@
+package conf
+
+import (
+	"sync"
+	"time"
+)
+
+// DebouncedWriter returns functions to write conf into the config file at
+// most once per interval d, e.g. for a settings UI that changes the config
+// frequently. write schedules conf to be written after d, and calls within
+// that interval only replace the pending value. flush writes the pending
+// value immediately and returns the error of the write, or of an earlier
+// scheduled write that failed. stop cancels the timer and flushes; writes
+// after stop are ignored. Close stops the writer as well.
+func (c *Context) DebouncedWriter(d time.Duration) (write func(conf interface{}), flush func() error, stop func()) {
+	var (
+		writeMu sync.Mutex // serializes writes, so the latest value wins
+		mu      sync.Mutex // guards the fields below
+		pending interface{}
+		dirty   bool
+		timer   *time.Timer
+		stopped bool
+		lastErr error
+	)
+	flush = func() error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		mu.Lock()
+		if timer != nil {
+			timer.Stop()
+			timer = nil
+		}
+		conf, ok, err := pending, dirty, lastErr
+		pending, dirty, lastErr = nil, false, nil
+		mu.Unlock()
+		if !ok {
+			return err
+		}
+		return c.Write(conf)
+	}
+	write = func(conf interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		if stopped {
+			return
+		}
+		pending, dirty = conf, true
+		if timer == nil {
+			timer = time.AfterFunc(d, func() {
+				if err := flush(); err != nil {
+					mu.Lock()
+					lastErr = err
+					mu.Unlock()
+				}
+			})
+		}
+	}
+	stopWriter := func() error {
+		mu.Lock()
+		stopped = true
+		mu.Unlock()
+		return flush()
+	}
+	remove := c.onClose(stopWriter)
+	stop = func() {
+		remove()
+		stopWriter()
+	}
+	return write, flush, stop
+}
@@ -0,0 +1,84 @@
+package conf
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDebouncedWriter(t *testing.T) {
+	// Initialize config context that counts writes
+	var writes int32
+	conf := Build().Directory(t.TempDir()).JSON().OnWrite(func(data []byte) ([]byte, error) {
+		atomic.AddInt32(&writes, 1)
+		return data, nil
+	}).Create()
+
+	write, flush, stop := conf.DebouncedWriter(50 * time.Millisecond)
+	defer stop()
+
+	cfg := TestConfig{"Just testing", 0, struct{ Field string }{"test"}}
+	for i := 1; i <= 100; i++ {
+		cfg.Number = i
+		write(cfg)
+	}
+	if n := atomic.LoadInt32(&writes); n != 0 {
+		t.Errorf("Expected no writes within the interval, got %d", n)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	if n := atomic.LoadInt32(&writes); n != 1 {
+		t.Errorf("Expected 1 write, got %d", n)
+	}
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead != cfg {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+
+	// Flush writes immediately
+	cfg.Number = 200
+	write(cfg)
+	if err := flush(); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&writes); n != 2 {
+		t.Errorf("Expected 2 writes, got %d", n)
+	}
+
+	// Nothing pending
+	if err := flush(); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&writes); n != 2 {
+		t.Errorf("Expected 2 writes, got %d", n)
+	}
+}
+
+func TestDebouncedWriterStop(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+	write, _, stop := conf.DebouncedWriter(time.Hour)
+
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	write(cfg)
+	stop()
+
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead != cfg {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+
+	// Writes after stop are ignored
+	write(TestConfig{})
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead != cfg {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+}
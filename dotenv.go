@@ -0,0 +1,87 @@
+package conf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+func dotenvMarshal(v interface{}) ([]byte, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil, ErrNotStruct
+	}
+	var buf bytes.Buffer
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key, ok := fieldKey(t.Field(i))
+		if !ok {
+			continue
+		}
+		s, err := formatValue(rv.Field(i))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", key, err)
+		}
+		if strings.ContainsAny(s, " \t\r\n#\"'\\") {
+			s = strconv.Quote(s)
+		}
+		fmt.Fprintf(&buf, "%s=%s\n", key, s)
+	}
+	return buf.Bytes(), nil
+}
+
+func dotenvUnmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return ErrNotStruct
+	}
+	rv = rv.Elem()
+	fields := make(map[string]reflect.Value)
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if key, ok := fieldKey(t.Field(i)); ok {
+			fields[key] = rv.Field(i)
+		}
+	}
+
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.Index(line, "=")
+		if i < 0 {
+			return fmt.Errorf("line %d: missing '='", n)
+		}
+		key, value := strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		if strings.HasPrefix(value, "\"") {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return fmt.Errorf("line %d: %v", n, err)
+			}
+			value = unquoted
+		}
+		field, ok := fields[key]
+		if !ok {
+			continue
+		}
+		if err := setValue(field, value); err != nil {
+			return fmt.Errorf("line %d: %s: %v", n, key, err)
+		}
+	}
+	return s.Err()
+}
+
+// DotEnv sets the encoding to the .env format of KEY=VALUE lines.
+// Only flat structs are supported. The key of a field is its name, or
+// the name given in its conf tag.
+func (b *Builder) DotEnv() *Builder {
+	if b.ctx.File == "" {
+		b.ctx.File = ".env"
+	}
+	return b.codec(FormatDotEnv, dotenvMarshal, dotenvUnmarshal)
+}
@@ -0,0 +1,64 @@
+package conf
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+type DotEnvTestConfig struct {
+	Name    string `conf:"APP_NAME"`
+	Port    int    `conf:"APP_PORT"`
+	Debug   bool
+	Ignored string `conf:"-"`
+}
+
+func TestDotEnv(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).DotEnv().Create()
+	if conf.File != ".env" {
+		t.Errorf("Unexpected file name: %s", conf.File)
+	}
+
+	cfg := DotEnvTestConfig{"Just # testing", 8080, true, "ignored"}
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfgRead DotEnvTestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Ignored = ""
+	if cfg != cfgRead {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+}
+
+func TestDotEnvComments(t *testing.T) {
+	dir := t.TempDir()
+	conf := Build().Directory(dir).DotEnv().Create()
+	data := "# A comment\n\nAPP_NAME=test\nAPP_PORT = 80\nUNKNOWN=1\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, ".env"), []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfgRead DotEnvTestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead.Name != "test" || cfgRead.Port != 80 {
+		t.Errorf("Unexpected config: %v", cfgRead)
+	}
+}
+
+func TestDotEnvUnsupported(t *testing.T) {
+	// Nested structs cannot be read back, so they are not written
+	conf := Build().Directory(t.TempDir()).DotEnv().Create()
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if err := conf.Write(cfg); err == nil {
+		t.Error("Expected error for nested struct")
+	}
+	if exists, _ := conf.Exists(); exists {
+		t.Error("Expected no config file")
+	}
+}
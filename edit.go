@@ -0,0 +1,61 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrNoEditor is returned by Edit if no editor can be found.
+var ErrNoEditor = errors.New("No editor found")
+
+// lookPath and runEditor are replaced in tests.
+var (
+	lookPath  = exec.LookPath
+	runEditor = func(name string, args ...string) error {
+		cmd := exec.Command(name, args...)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+)
+
+// Edit opens the config file in the editor of the user and waits until
+// the editor exits, e.g. for a "config edit" command. The file is created
+// first if it does not exist. The editor is taken from the EDITOR
+// environment variable, which may contain arguments like "code --wait",
+// and defaults to vi, or notepad on Windows. Read the config afterwards
+// to validate the changes.
+func (c *Context) Edit() error {
+	if c.stdio() {
+		return c.wrapErr("edit", ErrStdio)
+	}
+	name, args, err := editor()
+	if err != nil {
+		return c.wrapErr("edit", err)
+	}
+	if err := c.Touch(); err != nil {
+		return err
+	}
+	return c.wrapErr("edit", runEditor(name, append(args, c.Path())...))
+}
+
+// editor returns the path and arguments of the editor of the user.
+func editor() (name string, args []string, err error) {
+	fields := strings.Fields(os.Getenv("EDITOR"))
+	if len(fields) == 0 {
+		fields = []string{"vi"}
+		if runtime.GOOS == "windows" {
+			fields = []string{"notepad"}
+		}
+	}
+	name, err = lookPath(fields[0])
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %s: %v", ErrNoEditor, fields[0], err)
+	}
+	return name, fields[1:], nil
+}
@@ -0,0 +1,83 @@
+package conf
+
+import (
+	"errors"
+	"os/exec"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// stubEditor replaces the editor lookup and runner for the duration of
+// the test. Only the given commands are found.
+func stubEditor(t *testing.T, found ...string) (calls *[][]string) {
+	calls = new([][]string)
+	oldLookPath, oldRunEditor := lookPath, runEditor
+	t.Cleanup(func() {
+		lookPath, runEditor = oldLookPath, oldRunEditor
+	})
+	lookPath = func(file string) (string, error) {
+		for _, f := range found {
+			if f == file {
+				return "/usr/bin/" + file, nil
+			}
+		}
+		return "", exec.ErrNotFound
+	}
+	runEditor = func(name string, args ...string) error {
+		*calls = append(*calls, append([]string{name}, args...))
+		return nil
+	}
+	return calls
+}
+
+func TestEdit(t *testing.T) {
+	// Initialize config context
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+	calls := stubEditor(t, "code")
+	t.Setenv("EDITOR", "code --wait")
+
+	if err := conf.Edit(); err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]string{{"/usr/bin/code", "--wait", conf.Path()}}
+	if !reflect.DeepEqual(*calls, expected) {
+		t.Errorf("Unexpected editor calls: %v", *calls)
+	}
+	if ok, err := conf.Exists(); err != nil || !ok {
+		t.Errorf("Expected config file to be created: %v", err)
+	}
+}
+
+func TestEditDefault(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+	fallback := "vi"
+	if runtime.GOOS == "windows" {
+		fallback = "notepad"
+	}
+	calls := stubEditor(t, fallback)
+	t.Setenv("EDITOR", "")
+
+	if err := conf.Edit(); err != nil {
+		t.Fatal(err)
+	}
+	if len(*calls) != 1 || (*calls)[0][0] != "/usr/bin/"+fallback {
+		t.Errorf("Unexpected editor calls: %v", *calls)
+	}
+}
+
+func TestEditNotFound(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+	calls := stubEditor(t)
+	t.Setenv("EDITOR", "missing-editor")
+
+	if err := conf.Edit(); !errors.Is(err, ErrNoEditor) {
+		t.Errorf("Expected ErrNoEditor, got %v", err)
+	}
+	if len(*calls) != 0 {
+		t.Errorf("Expected no editor calls, got %v", *calls)
+	}
+	if ok, _ := conf.Exists(); ok {
+		t.Error("Expected no config file")
+	}
+}
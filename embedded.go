@@ -0,0 +1,30 @@
+package conf
+
+import (
+	"io/fs"
+)
+
+// EmbeddedDefaults sets a default config file in fsys, e.g. an embed.FS
+// compiled into the binary. If the config file does not exist, Read
+// decodes the embedded file instead, using the same encoding:
+//
+//	//go:embed default.json
+//	var defaults embed.FS
+//
+//	conf.Build().App("myapp").JSON().EmbeddedDefaults(defaults, "default.json").Create()
+func (b *Builder) EmbeddedDefaults(fsys fs.FS, name string) *Builder {
+	b.ctx.EmbeddedFS = fsys
+	b.ctx.EmbeddedFile = name
+	return b
+}
+
+// decodeEmbedded decodes the embedded default config file into the value
+// pointed to by conf. The embedded file is neither compressed nor
+// encrypted, so only the encoding of the context applies.
+func (c *Context) decodeEmbedded(conf interface{}) error {
+	data, err := fs.ReadFile(c.EmbeddedFS, c.EmbeddedFile)
+	if err != nil {
+		return err
+	}
+	return c.decode(data, conf)
+}
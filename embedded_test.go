@@ -0,0 +1,47 @@
+package conf
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestEmbeddedDefaults(t *testing.T) {
+	// Initialize config context
+	fsys := fstest.MapFS{
+		"default.json": &fstest.MapFile{
+			Data: []byte(`{"String": "embedded", "Number": 1, "Sub": {"Field": "default"}}`),
+		},
+	}
+	conf := Build().Directory(t.TempDir()).JSON().EmbeddedDefaults(fsys, "default.json").Create()
+
+	// A missing config file reads the embedded defaults
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	expected := TestConfig{"embedded", 1, struct{ Field string }{"default"}}
+	if cfgRead != expected {
+		t.Errorf("Configs differ: %v, %v", expected, cfgRead)
+	}
+
+	// An existing config file is read instead
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfgRead = TestConfig{}
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead != cfg {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+}
+
+func TestEmbeddedDefaultsMissing(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().EmbeddedDefaults(fstest.MapFS{}, "default.json").Create()
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err == nil {
+		t.Error("Expected error for missing embedded file")
+	}
+}
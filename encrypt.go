@@ -0,0 +1,58 @@
+package conf
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+var ErrCiphertext = errors.New("Encrypted config is too short")
+
+// Encrypted enables AES-GCM encryption of the config file with the given
+// key, which must be 16, 24 or 32 bytes long to select AES-128, AES-192
+// or AES-256. Encrypted panics if the key has a different length.
+func (b *Builder) Encrypted(key []byte) *Builder {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		panic(fmt.Sprintf("conf: invalid encryption key length %d, must be 16, 24 or 32 bytes", len(key)))
+	}
+	b.ctx.EncryptionKey = key
+	return b
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encrypt seals data with AES-GCM and prepends the random nonce.
+func encrypt(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, data, nil), nil
+}
+
+// decrypt opens data sealed by encrypt.
+func decrypt(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, ErrCiphertext
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
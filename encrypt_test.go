@@ -0,0 +1,49 @@
+package conf
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func TestEncrypted(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	conf := Build().Directory(t.TempDir()).JSON().Encrypted(key).Create()
+
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"secret"}}
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	// The file does not contain plaintext
+	data, err := ioutil.ReadFile(conf.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("secret")) || bytes.Contains(data, []byte("Just testing")) {
+		t.Errorf("Config is not encrypted: %s", data)
+	}
+
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfg != cfgRead {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+
+	// Reading with the wrong key fails
+	other := Build().Directory(conf.Directory).JSON().Encrypted(bytes.Repeat([]byte("x"), 32)).Create()
+	if err := other.Read(&cfgRead); err == nil {
+		t.Error("Expected error when reading with the wrong key")
+	}
+}
+
+func TestEncryptedInvalidKey(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for invalid key length")
+		}
+	}()
+	Build().Encrypted([]byte("short"))
+}
@@ -0,0 +1,139 @@
+package conf
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix sets the prefix of environment variables that override
+// config fields in ReadWithEnv.
+func (b *Builder) EnvPrefix(prefix string) *Builder {
+	b.ctx.EnvPrefix = prefix
+	return b
+}
+
+// ReadWithEnv reads the config file into the struct pointed to by conf
+// and then overrides each field that has a matching environment variable.
+// The variable name is the upper-cased field key, prefixed with the
+// EnvPrefix of the context, e.g. MYAPP_PORT. Fields of nested structs
+// are joined by underscores, e.g. MYAPP_SUB_FIELD.
+//
+// The key of a field is its name, or the name given in its conf tag,
+// e.g. `conf:"port"`. Fields tagged with `conf:"-"` are skipped.
+func (c *Context) ReadWithEnv(conf interface{}) error {
+	if err := c.Read(conf); err != nil {
+		return err
+	}
+	return c.setEnv(conf)
+}
+
+// EnvOnly makes the context read the config solely from environment
+// variables with the given prefix, like ReadWithEnv but without a config
+// file, e.g. in containers. Defaults and the validator still apply. There
+// is no file to write, so the context is read-only and Write returns
+// ErrReadOnly.
+func (b *Builder) EnvOnly(prefix string) *Builder {
+	b.ctx.EnvPrefix = prefix
+	b.ctx.EnvOnly = true
+	b.ctx.ReadOnly = true
+	return b
+}
+
+// setEnv overrides the fields of the struct pointed to by conf with their
+// environment variables.
+func (c *Context) setEnv(conf interface{}) error {
+	v := reflect.ValueOf(conf)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return ErrNotStruct
+	}
+	return setEnvFields(v.Elem(), c.EnvPrefix)
+}
+
+func envName(prefix, name string) string {
+	if prefix == "" {
+		return strings.ToUpper(name)
+	}
+	return strings.ToUpper(prefix + "_" + name)
+}
+
+func setEnvFields(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key, ok := fieldKey(field)
+		if !ok {
+			continue
+		}
+		name := envName(prefix, key)
+		if field.Type.Kind() == reflect.Struct {
+			if err := setEnvFields(v.Field(i), name); err != nil {
+				return err
+			}
+			continue
+		}
+		s, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setValue(v.Field(i), s); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// formatValue returns the string representation of v that setValue
+// parses. It returns an error for types setValue does not support.
+func formatValue(v reflect.Value) (string, error) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
+
+// setValue parses s into v according to the kind of v.
+func setValue(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
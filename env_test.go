@@ -0,0 +1,104 @@
+package conf
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+)
+
+type EnvTestConfig struct {
+	Name  string
+	Port  int
+	Debug bool
+	Sub   struct {
+		Field string
+	}
+}
+
+func TestReadWithEnv(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().EnvPrefix("goconftest").Create()
+	cfg := EnvTestConfig{Name: "file", Port: 80}
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("GOCONFTEST_PORT", "8080")
+	t.Setenv("GOCONFTEST_DEBUG", "true")
+	t.Setenv("GOCONFTEST_SUB_FIELD", "env")
+
+	var cfgRead EnvTestConfig
+	if err := conf.ReadWithEnv(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead.Name != "file" {
+		t.Errorf("Name should not be overridden: %v", cfgRead.Name)
+	}
+	if cfgRead.Port != 8080 {
+		t.Errorf("Port was not overridden: %v", cfgRead.Port)
+	}
+	if !cfgRead.Debug {
+		t.Errorf("Debug was not overridden: %v", cfgRead.Debug)
+	}
+	if cfgRead.Sub.Field != "env" {
+		t.Errorf("Sub.Field was not overridden: %v", cfgRead.Sub.Field)
+	}
+
+	t.Setenv("GOCONFTEST_PORT", "invalid")
+	if err := conf.ReadWithEnv(&cfgRead); err == nil {
+		t.Error("Expected error for invalid int")
+	}
+}
+
+func TestReadWithEnvTags(t *testing.T) {
+	type config struct {
+		Untagged string
+		Port     int    `conf:"http_port"`
+		Skipped  string `conf:"-"`
+	}
+	conf := Build().Directory(t.TempDir()).JSON().EnvPrefix("goconftest").Create()
+
+	t.Setenv("GOCONFTEST_UNTAGGED", "env")
+	t.Setenv("GOCONFTEST_PORT", "1")
+	t.Setenv("GOCONFTEST_HTTP_PORT", "8080")
+	t.Setenv("GOCONFTEST_SKIPPED", "env")
+
+	var cfgRead config
+	if err := conf.ReadWithEnv(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	expected := config{"env", 8080, ""}
+	if cfgRead != expected {
+		t.Errorf("Configs differ: %v, %v", expected, cfgRead)
+	}
+}
+
+func TestEnvOnly(t *testing.T) {
+	// Initialize config context without any config file
+	dir := t.TempDir()
+	conf := Build().Directory(dir).EnvOnly("goconftest").Create()
+
+	t.Setenv("GOCONFTEST_NAME", "env")
+	t.Setenv("GOCONFTEST_PORT", "8080")
+	t.Setenv("GOCONFTEST_SUB_FIELD", "nested")
+
+	var cfgRead EnvTestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	expected := EnvTestConfig{Name: "env", Port: 8080}
+	expected.Sub.Field = "nested"
+	if cfgRead != expected {
+		t.Errorf("Configs differ: %v, %v", expected, cfgRead)
+	}
+
+	if err := conf.Write(cfgRead); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly, got %v", err)
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Errorf("Expected no files, got %d", len(files))
+	}
+}
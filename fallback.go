@@ -0,0 +1,67 @@
+package conf
+
+import (
+	"reflect"
+)
+
+// Codec is a pair of functions for encoding and decoding.
+type Codec struct {
+	Marshal   MarshalFunc
+	Unmarshal UnmarshalFunc
+}
+
+// Codec returns the encoding of the context, e.g. to use it as fallback
+// for another context.
+func (c *Context) Codec() Codec {
+	return Codec{c.Marshal, c.Unmarshal}
+}
+
+// Fallback sets codecs whose unmarshal functions Read tries in order if
+// the encoding of the context fails to decode the config file, e.g. while
+// migrating users from YAML to JSON:
+//
+//	yaml := conf.Build().YAML().Create().Codec()
+//	conf.Build().App("myapp").JSON().Fallback(yaml).Create()
+//
+// Write always uses the encoding of the context.
+func (b *Builder) Fallback(codecs ...Codec) *Builder {
+	b.fallbacks = codecs
+	return b
+}
+
+// fallbackUnmarshal returns an unmarshal function that tries primary and
+// then the fallbacks until one succeeds. Each one decodes into a copy of
+// the value pointed to by v, so failed attempts leave no partial result.
+// If all fail, the error of primary is returned.
+func fallbackUnmarshal(primary UnmarshalFunc, fallbacks []Codec) UnmarshalFunc {
+	return func(data []byte, v interface{}) error {
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Ptr || rv.IsNil() {
+			return primary(data, v)
+		}
+		var first error
+		for i := -1; i < len(fallbacks); i++ {
+			u := primary
+			if i >= 0 {
+				u = fallbacks[i].Unmarshal
+			}
+			if u == nil {
+				continue
+			}
+			attempt := reflect.New(rv.Elem().Type())
+			attempt.Elem().Set(rv.Elem())
+			err := u(data, attempt.Interface())
+			if err == nil {
+				rv.Elem().Set(attempt.Elem())
+				return nil
+			}
+			if first == nil {
+				first = err
+			}
+		}
+		if first == nil {
+			return ErrNoUnmarshal
+		}
+		return first
+	}
+}
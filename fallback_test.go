@@ -0,0 +1,46 @@
+package conf
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFallback(t *testing.T) {
+	// Initialize a legacy YAML config
+	dir := t.TempDir()
+	legacy := Build().Directory(dir).File("config.json").YAML().Create()
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if err := legacy.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	// JSON without fallback fails
+	conf := Build().Directory(dir).JSON().Create()
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err == nil {
+		t.Fatal("Expected error reading YAML as JSON")
+	}
+
+	// JSON with YAML fallback succeeds
+	yaml := Build().YAML().Create().Codec()
+	conf = Build().Directory(dir).JSON().Fallback(yaml).Create()
+	cfgRead = TestConfig{}
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfg != cfgRead {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+
+	// Write uses JSON
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+	data, err := conf.ReadBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !json.Valid(data) {
+		t.Errorf("Expected JSON: %s", data)
+	}
+}
@@ -0,0 +1,121 @@
+package conf
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+)
+
+var ErrUnknownFormat = errors.New("Unknown config file format")
+
+// Format identifies the encoding of a context.
+type Format int
+
+const (
+	FormatNone Format = iota
+	FormatCustom
+	FormatJSON
+	FormatYAML
+	FormatTOML
+	FormatXML
+	FormatGob
+	FormatINI
+	FormatDotEnv
+	FormatCBOR
+	FormatMsgPack
+	FormatHCL
+	FormatCSV
+	FormatJSONC
+	FormatProperties
+	FormatProto
+	FormatProtoText
+)
+
+var formatNames = []string{
+	FormatNone:       "none",
+	FormatCustom:     "custom",
+	FormatJSON:       "JSON",
+	FormatYAML:       "YAML",
+	FormatTOML:       "TOML",
+	FormatXML:        "XML",
+	FormatGob:        "Gob",
+	FormatINI:        "INI",
+	FormatDotEnv:     ".env",
+	FormatCBOR:       "CBOR",
+	FormatMsgPack:    "MessagePack",
+	FormatHCL:        "HCL",
+	FormatCSV:        "CSV",
+	FormatJSONC:      "JSONC",
+	FormatProperties: "Properties",
+	FormatProto:      "Protobuf",
+	FormatProtoText:  "Protobuf text",
+}
+
+func (f Format) String() string {
+	if f < 0 || int(f) >= len(formatNames) {
+		return "unknown"
+	}
+	return formatNames[f]
+}
+
+// Format returns the encoding of the context. It is FormatCustom if the
+// encoding was set with Builder.Marshaller.
+func (c *Context) Format() Format {
+	return c.format
+}
+
+// codec sets the marshaller of a built-in format.
+func (b *Builder) codec(f Format, m MarshalFunc, u UnmarshalFunc) *Builder {
+	b.Marshaller(m, u)
+	b.ctx.format = f
+	return b
+}
+
+// extFormats maps file extensions to the builder methods of their format.
+var extFormats = map[string]func(*Builder) *Builder{
+	".json":       (*Builder).JSON,
+	".jsonc":      (*Builder).JSONC,
+	".yaml":       (*Builder).YAML,
+	".yml":        (*Builder).YAML,
+	".toml":       (*Builder).TOML,
+	".xml":        (*Builder).XML,
+	".gob":        (*Builder).Gob,
+	".ini":        (*Builder).INI,
+	".env":        (*Builder).DotEnv,
+	".cbor":       (*Builder).CBOR,
+	".msgpack":    (*Builder).MsgPack,
+	".hcl":        (*Builder).HCL,
+	".csv":        (*Builder).CSV,
+	".properties": (*Builder).Properties,
+	".pb":         (*Builder).Proto,
+	".txtpb":      (*Builder).ProtoText,
+}
+
+// formatByExt returns the builder method of the format matching the
+// extension of file. A ".gz" suffix also enables compression.
+func formatByExt(file string) (func(*Builder) *Builder, bool) {
+	gz := strings.HasSuffix(strings.ToLower(file), ".gz")
+	if gz {
+		file = file[:len(file)-len(".gz")]
+	}
+	fn, ok := extFormats[strings.ToLower(filepath.Ext(file))]
+	if !ok || !gz {
+		return fn, ok
+	}
+	return func(b *Builder) *Builder {
+		return fn(b).Compressed()
+	}, true
+}
+
+// FromFile returns a context for the config file at path, with the
+// encoding inferred from its extension. It returns ErrUnknownFormat if the
+// extension does not belong to a supported format.
+func FromFile(path string) (*Context, error) {
+	dir, file := filepath.Split(path)
+	b := Build().Directory(dir).File(file)
+	fn, ok := formatByExt(file)
+	if !ok {
+		return nil, ErrUnknownFormat
+	}
+	return fn(b).Create(), nil
+}
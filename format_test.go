@@ -0,0 +1,95 @@
+package conf
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := map[Format]*Builder{
+		FormatNone:   Build(),
+		FormatCustom: Build().Marshaller(json.Marshal, json.Unmarshal),
+		FormatJSON:   Build().JSON(),
+		FormatYAML:   Build().YAML(),
+		FormatCSV:    Build().CSV(),
+	}
+	for expected, b := range tests {
+		if f := b.Create().Format(); f != expected {
+			t.Errorf("Expected %v, got %v", expected, f)
+		}
+	}
+
+	if s := FormatYAML.String(); s != "YAML" {
+		t.Errorf("Unexpected name: %s", s)
+	}
+	if s := Format(-1).String(); s != "unknown" {
+		t.Errorf("Unexpected name: %s", s)
+	}
+}
+
+func TestFormatByExt(t *testing.T) {
+	tests := map[string]Format{
+		"config.json":    FormatJSON,
+		"config.yaml":    FormatYAML,
+		"config.YML":     FormatYAML,
+		"config.toml":    FormatTOML,
+		"config.xml":     FormatXML,
+		"config.json.gz": FormatJSON,
+		"config.unknown": FormatNone,
+		"config":         FormatNone,
+	}
+	for file, expected := range tests {
+		conf := Build().File(file).Create()
+		if f := conf.Format(); f != expected {
+			t.Errorf("%s: expected %v, got %v", file, expected, f)
+		}
+		if expected == FormatNone && conf.Marshal != nil {
+			t.Errorf("%s: unexpected marshaller", file)
+		}
+		if gz := strings.HasSuffix(file, ".gz"); conf.Compressed != gz {
+			t.Errorf("%s: expected compression %v", file, gz)
+		}
+	}
+
+	// An explicit encoding is kept
+	conf := Build().File("config.yaml").JSON().Create()
+	if f := conf.Format(); f != FormatJSON {
+		t.Errorf("Expected JSON, got %v", f)
+	}
+}
+
+func TestFromFile(t *testing.T) {
+	conf, err := FromFile("testdata/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	expected := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if cfgRead != expected {
+		t.Errorf("Configs differ: %v, %v", expected, cfgRead)
+	}
+
+	if _, err := FromFile("testdata/config.unknown"); err != ErrUnknownFormat {
+		t.Errorf("Expected ErrUnknownFormat, got %v", err)
+	}
+
+	// A ".gz" suffix enables compression
+	compressed := Build().Directory(t.TempDir()).JSON().Compressed().Create()
+	if err := compressed.Write(expected); err != nil {
+		t.Fatal(err)
+	}
+	if conf, err = FromFile(compressed.Path()); err != nil {
+		t.Fatal(err)
+	}
+	cfgRead = TestConfig{}
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead != expected {
+		t.Errorf("Configs differ: %v, %v", expected, cfgRead)
+	}
+}
@@ -0,0 +1,91 @@
+package conf
+
+import (
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// File is an open file of a FileSystem.
+type File interface {
+	io.Reader
+	io.Writer
+	io.Closer
+	Stat() (os.FileInfo, error)
+	Sync() error
+}
+
+// FileSystem is the file system that config files are read from and
+// written to. Its methods behave like the functions of package os.
+type FileSystem interface {
+	Open(name string) (File, error)
+	OpenFile(name string, flag int, perm os.FileMode) (File, error)
+	MkdirAll(path string, perm os.FileMode) error
+	Stat(name string) (os.FileInfo, error)
+	Rename(oldpath, newpath string) error
+	Remove(name string) error
+}
+
+// FileSystem sets the file system of the config file, e.g. an in-memory
+// file system in tests. It defaults to the file system of the operating
+// system. FileLock and Watch always use the operating system.
+func (b *Builder) FileSystem(fsys FileSystem) *Builder {
+	b.ctx.FileSystem = fsys
+	return b
+}
+
+// fsys returns the file system of the context.
+func (c *Context) fsys() FileSystem {
+	if c.FileSystem == nil {
+		return osFS{}
+	}
+	return c.FileSystem
+}
+
+// readFile returns the contents of the file at path.
+func (c *Context) readFile(path string) ([]byte, error) {
+	f, err := c.fsys().Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readAll(f)
+}
+
+// missing reports whether the file at path does not exist.
+func (c *Context) missing(path string) bool {
+	_, err := c.fsys().Stat(path)
+	return errors.Is(err, os.ErrNotExist)
+}
+
+// osFS is the file system of the operating system.
+type osFS struct{}
+
+func (osFS) Open(name string) (File, error) {
+	return os.Open(name)
+}
+
+func (osFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	return os.OpenFile(name, flag, perm)
+}
+
+func (osFS) MkdirAll(path string, perm os.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+
+func (osFS) Stat(name string) (os.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (osFS) Rename(oldpath, newpath string) error {
+	return os.Rename(oldpath, newpath)
+}
+
+func (osFS) Remove(name string) error {
+	return os.Remove(name)
+}
+
+func (osFS) Chtimes(name string, atime, mtime time.Time) error {
+	return os.Chtimes(name, atime, mtime)
+}
@@ -0,0 +1,168 @@
+package conf
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memFS is an in-memory file system. Directories are implicit.
+type memFS struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func newMemFS() *memFS {
+	return &memFS{files: map[string][]byte{}}
+}
+
+func (m *memFS) Open(name string) (File, error) {
+	return m.OpenFile(name, os.O_RDONLY, 0)
+}
+
+func (m *memFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.files[name]
+	switch {
+	case ok && flag&os.O_EXCL != 0:
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrExist}
+	case !ok && flag&os.O_CREATE == 0:
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	case flag&os.O_TRUNC != 0:
+		data = nil
+	}
+	f := &memFile{fs: m, name: name}
+	if flag&(os.O_WRONLY|os.O_RDWR) != 0 {
+		f.writable = true
+		f.buf.Write(data)
+		m.files[name] = f.buf.Bytes()
+	} else {
+		f.buf.Write(data)
+	}
+	return f, nil
+}
+
+func (m *memFS) MkdirAll(path string, perm os.FileMode) error {
+	return nil
+}
+
+func (m *memFS) Stat(name string) (os.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.files[name]
+	if !ok {
+		return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+	}
+	return memFileInfo{filepath.Base(name), int64(len(data))}, nil
+}
+
+func (m *memFS) Rename(oldpath, newpath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.files[oldpath]
+	if !ok {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: os.ErrNotExist}
+	}
+	m.files[newpath] = data
+	delete(m.files, oldpath)
+	return nil
+}
+
+func (m *memFS) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.files[name]; !ok {
+		return &os.PathError{Op: "remove", Path: name, Err: os.ErrNotExist}
+	}
+	delete(m.files, name)
+	return nil
+}
+
+// memFile is an open file of a memFS.
+type memFile struct {
+	fs       *memFS
+	name     string
+	buf      bytes.Buffer
+	writable bool
+}
+
+func (f *memFile) Read(p []byte) (int, error) {
+	return f.buf.Read(p)
+}
+
+func (f *memFile) Write(p []byte) (int, error) {
+	if !f.writable {
+		return 0, os.ErrPermission
+	}
+	n, err := f.buf.Write(p)
+	f.fs.mu.Lock()
+	f.fs.files[f.name] = f.buf.Bytes()
+	f.fs.mu.Unlock()
+	return n, err
+}
+
+func (f *memFile) Stat() (os.FileInfo, error) {
+	return memFileInfo{filepath.Base(f.name), int64(f.buf.Len())}, nil
+}
+
+func (f *memFile) Sync() error  { return nil }
+func (f *memFile) Close() error { return nil }
+
+type memFileInfo struct {
+	name string
+	size int64
+}
+
+func (fi memFileInfo) Name() string       { return fi.name }
+func (fi memFileInfo) Size() int64        { return fi.size }
+func (fi memFileInfo) Mode() os.FileMode  { return 0600 }
+func (fi memFileInfo) ModTime() time.Time { return time.Time{} }
+func (fi memFileInfo) IsDir() bool        { return false }
+func (fi memFileInfo) Sys() interface{}   { return nil }
+
+func TestFileSystem(t *testing.T) {
+	// Initialize config context on an in-memory file system
+	fsys := newMemFS()
+	conf := Build().Directory("/nonexistent").JSON().FileSystem(fsys).Create()
+
+	// Example config
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+
+	// Write config
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fsys.files[conf.Path()]; !ok {
+		t.Errorf("Expected %s in memory, got %v", conf.Path(), fsys.files)
+	}
+	if _, err := os.Stat(conf.Path()); !os.IsNotExist(err) {
+		t.Errorf("Expected no file on disk: %v", err)
+	}
+
+	// Read config
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfg != cfgRead {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+
+	if ok, err := conf.Exists(); err != nil || !ok {
+		t.Errorf("Expected config to exist: %v", err)
+	}
+	if err := conf.WriteNew(cfg); !errors.Is(err, os.ErrExist) {
+		t.Errorf("Expected ErrExist, got %v", err)
+	}
+	if err := conf.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if len(fsys.files) != 0 {
+		t.Errorf("Expected empty file system, got %v", fsys.files)
+	}
+}
@@ -0,0 +1,26 @@
+package conf
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+func gobMarshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gobUnmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// Gob sets the encoding to the binary gob format.
+func (b *Builder) Gob() *Builder {
+	if b.ctx.File == "" {
+		b.ctx.File = "config.gob"
+	}
+	return b.codec(FormatGob, gobMarshal, gobUnmarshal)
+}
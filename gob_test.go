@@ -0,0 +1,33 @@
+package conf
+
+import (
+	"testing"
+)
+
+func TestGob(t *testing.T) {
+	// Initialize config context
+	conf := Build().Directory(t.TempDir()).Gob().Create()
+	if conf.File != "config.gob" {
+		t.Errorf("Unexpected file name: %s", conf.File)
+	}
+
+	// Example config
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+
+	// Write config
+	err := conf.Write(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Read config
+	var cfgRead TestConfig
+	err = conf.Read(&cfgRead)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg != cfgRead {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+}
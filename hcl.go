@@ -0,0 +1,22 @@
+package conf
+
+import (
+	"errors"
+
+	"github.com/hashicorp/hcl"
+)
+
+var ErrHCLMarshal = errors.New("HCL encoding is not supported")
+
+func hclMarshal(v interface{}) ([]byte, error) {
+	return nil, ErrHCLMarshal
+}
+
+// HCL sets the encoding to HashiCorp's HCL format.
+// Only reading is supported, Write returns ErrHCLMarshal.
+func (b *Builder) HCL() *Builder {
+	if b.ctx.File == "" {
+		b.ctx.File = "config.hcl"
+	}
+	return b.codec(FormatHCL, hclMarshal, hcl.Unmarshal)
+}
@@ -0,0 +1,52 @@
+package conf
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHCL(t *testing.T) {
+	conf := Build().Directory("testdata").HCL().Create()
+	if conf.File != "config.hcl" {
+		t.Errorf("Unexpected file name: %s", conf.File)
+	}
+
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	expected := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if cfgRead != expected {
+		t.Errorf("Configs differ: %v, %v", expected, cfgRead)
+	}
+
+	conf = Build().Directory(t.TempDir()).HCL().Create()
+	if err := conf.Write(expected); !errors.Is(err, ErrHCLMarshal) {
+		t.Errorf("Expected ErrHCLMarshal, got %v", err)
+	}
+}
+
+func TestHCLDefaults(t *testing.T) {
+	defaults := TestConfig{"default", 1, struct{ Field string }{"default"}}
+
+	// HCL cannot marshal, so defaults are copied
+	conf := Build().Directory(t.TempDir()).HCL().Defaults(defaults).Create()
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead != defaults {
+		t.Errorf("Configs differ: %v, %v", defaults, cfgRead)
+	}
+
+	// The config file overrides them
+	conf = Build().Directory("testdata").HCL().Create()
+	cfgRead = TestConfig{}
+	if err := conf.ReadOrDefault(&cfgRead, defaults); err != nil {
+		t.Fatal(err)
+	}
+	expected := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if cfgRead != expected {
+		t.Errorf("Configs differ: %v, %v", expected, cfgRead)
+	}
+}
@@ -0,0 +1,69 @@
+package conf
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+)
+
+// ErrNoComments is returned by Write if the context has a header, but its
+// encoding does not support comments, e.g. JSON.
+var ErrNoComments = errors.New("Format does not support comments")
+
+// commentSyntax maps formats to the start and end of their comments.
+var commentSyntax = map[Format][2]string{
+	FormatYAML:       {"# ", ""},
+	FormatTOML:       {"# ", ""},
+	FormatINI:        {"; ", ""},
+	FormatDotEnv:     {"# ", ""},
+	FormatHCL:        {"# ", ""},
+	FormatJSONC:      {"// ", ""},
+	FormatProperties: {"# ", ""},
+	FormatProtoText:  {"# ", ""},
+	FormatXML:        {"<!-- ", " -->"},
+}
+
+// Header sets a comment that Write prepends to the config file, e.g.
+// "This file is managed by myapp - do not edit". Each line of text becomes
+// a comment in the syntax of the format, and Read strips it again. For
+// formats without comments, like JSON, Write returns ErrNoComments.
+func (b *Builder) Header(text string) *Builder {
+	b.ctx.Header = text
+	return b
+}
+
+// header returns the encoded header of the context.
+func (c *Context) header() ([]byte, error) {
+	syntax, ok := commentSyntax[c.format]
+	if !ok {
+		return nil, ErrNoComments
+	}
+	var buf bytes.Buffer
+	for _, line := range strings.Split(c.Header, "\n") {
+		buf.WriteString(strings.TrimRight(syntax[0]+line+syntax[1], " "))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// addHeader prepends the header of the context to the encoded data.
+func (c *Context) addHeader(data []byte) ([]byte, error) {
+	header, err := c.header()
+	if err != nil {
+		return nil, err
+	}
+	return append(header, data...), nil
+}
+
+// stripHeader removes the header of the context from the encoded data,
+// if present.
+func (c *Context) stripHeader(data []byte) []byte {
+	if c.Header == "" {
+		return data
+	}
+	header, err := c.header()
+	if err != nil {
+		return data
+	}
+	return bytes.TrimPrefix(data, header)
+}
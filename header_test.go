@@ -0,0 +1,58 @@
+package conf
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestHeader(t *testing.T) {
+	// Initialize config context
+	conf := Build().Directory(t.TempDir()).YAML().Header("This file is managed by myapp\ndo not edit").Create()
+
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+	data, err := conf.ReadBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("# This file is managed by myapp\n# do not edit\n")) {
+		t.Errorf("Expected header: %s", data)
+	}
+
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfg != cfgRead {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+}
+
+func TestHeaderXML(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).XML().Header("managed").Create()
+
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfg != cfgRead {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+}
+
+func TestHeaderJSON(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Header("managed").Create()
+	if err := conf.Write(TestConfig{}); !errors.Is(err, ErrNoComments) {
+		t.Errorf("Expected ErrNoComments, got %v", err)
+	}
+	if ok, _ := conf.Exists(); ok {
+		t.Error("Expected no config file")
+	}
+}
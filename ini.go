@@ -0,0 +1,43 @@
+package conf
+
+import (
+	"bytes"
+	"reflect"
+
+	"gopkg.in/ini.v1"
+)
+
+func iniMarshal(v interface{}) ([]byte, error) {
+	// ini only reflects from pointers to structs
+	if rv := reflect.ValueOf(v); rv.Kind() != reflect.Ptr {
+		p := reflect.New(rv.Type())
+		p.Elem().Set(rv)
+		v = p.Interface()
+	}
+	f := ini.Empty()
+	if err := ini.ReflectFrom(f, v); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func iniUnmarshal(data []byte, v interface{}) error {
+	f, err := ini.Load(data)
+	if err != nil {
+		return err
+	}
+	return f.MapTo(v)
+}
+
+// INI sets the encoding to the INI format. Nested structs are mapped
+// to sections.
+func (b *Builder) INI() *Builder {
+	if b.ctx.File == "" {
+		b.ctx.File = "config.ini"
+	}
+	return b.codec(FormatINI, iniMarshal, iniUnmarshal)
+}
@@ -0,0 +1,45 @@
+package conf
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestINI(t *testing.T) {
+	// Initialize config context
+	conf := Build().Directory(t.TempDir()).INI().Create()
+	if conf.File != "config.ini" {
+		t.Errorf("Unexpected file name: %s", conf.File)
+	}
+
+	// Example config
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+
+	// Write config
+	err := conf.Write(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nested structs are written as sections
+	data, err := ioutil.ReadFile(filepath.Join(conf.Directory, conf.File))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "[Sub]") {
+		t.Errorf("Missing section in config:\n%s", data)
+	}
+
+	// Read config
+	var cfgRead TestConfig
+	err = conf.Read(&cfgRead)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg != cfgRead {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+}
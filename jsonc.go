@@ -0,0 +1,63 @@
+package conf
+
+import (
+	"encoding/json"
+)
+
+// stripJSONComments replaces // line comments and /* block comments */
+// outside of string literals with spaces, keeping line breaks, so that
+// offsets in decoding errors still match the original data.
+func stripJSONComments(data []byte) []byte {
+	out := make([]byte, len(data))
+	copy(out, data)
+	inString, escaped := false, false
+	for i := 0; i < len(out); i++ {
+		ch := out[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case ch == '\\':
+				escaped = true
+			case ch == '"':
+				inString = false
+			}
+			continue
+		}
+		switch {
+		case ch == '"':
+			inString = true
+		case ch == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case ch == '/' && i+1 < len(out) && out[i+1] == '*':
+			out[i], out[i+1] = ' ', ' '
+			for i += 2; i < len(out); i++ {
+				if out[i] == '*' && i+1 < len(out) && out[i+1] == '/' {
+					out[i], out[i+1] = ' ', ' '
+					i++
+					break
+				}
+				if out[i] != '\n' {
+					out[i] = ' '
+				}
+			}
+		}
+	}
+	return out
+}
+
+func jsoncUnmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(stripJSONComments(data), v)
+}
+
+// JSONC sets the encoding to JSON with comments. Comments are ignored
+// when reading, and plain JSON is written.
+func (b *Builder) JSONC() *Builder {
+	if b.ctx.File == "" {
+		b.ctx.File = "config.jsonc"
+	}
+	b.codec(FormatJSONC, jsonMarshalIndent, jsoncUnmarshal)
+	return b.StreamEncoder(newJSONEncoder)
+}
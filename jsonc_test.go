@@ -0,0 +1,36 @@
+package conf
+
+import (
+	"testing"
+)
+
+func TestJSONC(t *testing.T) {
+	conf := Build().Directory("testdata").JSONC().Create()
+	if conf.File != "config.jsonc" {
+		t.Errorf("Unexpected file name: %s", conf.File)
+	}
+
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	expected := TestConfig{"http://example.com/*", 123, struct{ Field string }{`escaped " // quote`}}
+	if cfgRead != expected {
+		t.Errorf("Configs differ: %v, %v", expected, cfgRead)
+	}
+}
+
+func TestStripJSONComments(t *testing.T) {
+	tests := map[string]string{
+		`{"a": 1} // comment`:       `{"a": 1}           `,
+		"{/* a\nb */}":              "{    \n    }",
+		`{"a": "// not a comment"}`: `{"a": "// not a comment"}`,
+		`{"a": "\\"} // comment`:    `{"a": "\\"}           `,
+		`{} /* unterminated`:        `{}                `,
+	}
+	for in, expected := range tests {
+		if out := string(stripJSONComments([]byte(in))); out != expected {
+			t.Errorf("Stripping %q: expected %q, got %q", in, expected, out)
+		}
+	}
+}
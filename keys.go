@@ -0,0 +1,298 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var ErrNoKey = errors.New("Key not found")
+
+// readTree decodes the config file into a generic tree of maps and
+// slices. A missing config file is an empty map.
+func (c *Context) readTree() (interface{}, error) {
+	var v interface{}
+	if err := c.decodeFile(c.readPath(), &v); err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return make(map[string]interface{}), nil
+	}
+	return normalizeTree(v), nil
+}
+
+// normalizeTree converts the map[interface{}]interface{} values some
+// decoders produce for objects, e.g. CBOR, to map[string]interface{}.
+func normalizeTree(v interface{}) interface{} {
+	switch node := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(node))
+		for k, v := range node {
+			m[fmt.Sprint(k)] = normalizeTree(v)
+		}
+		return m
+	case map[string]interface{}:
+		for k, v := range node {
+			node[k] = normalizeTree(v)
+		}
+	case []interface{}:
+		for i, v := range node {
+			node[i] = normalizeTree(v)
+		}
+	}
+	return v
+}
+
+// ReadMap reads the config file into a new map, e.g. to inspect configs
+// generically. Nested objects are maps as well. If the config file does
+// not exist, the map is empty.
+func (c *Context) ReadMap() (map[string]interface{}, error) {
+	unlock, err := c.rlock()
+	if err != nil {
+		return nil, c.wrapErr("read", err)
+	}
+	defer unlock()
+	m := make(map[string]interface{})
+	if err := c.decodeFile(c.readPath(), &m); err != nil {
+		return nil, c.wrapErr("read", err)
+	}
+	return m, nil
+}
+
+// Get returns the value of a dotted key path like "Sub.Field" in the
+// config file. Elements of arrays are selected by their index, e.g.
+// "0.Name" if the config file is an array. It returns an error wrapping
+// ErrNoKey if the key does not exist or an index is out of range.
+func (c *Context) Get(key string) (interface{}, error) {
+	unlock, err := c.rlock()
+	if err != nil {
+		return nil, c.wrapErr("read", err)
+	}
+	defer unlock()
+	tree, err := c.readTree()
+	if err != nil {
+		return nil, c.wrapErr("read", err)
+	}
+	return lookupKey(tree, key)
+}
+
+// GetString returns the string value of a dotted key path like
+// "Sub.Field" in the config file.
+func (c *Context) GetString(key string) (string, error) {
+	v, err := c.Get(key)
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("key %s is not a string but %T", key, v)
+	}
+	return s, nil
+}
+
+// GetInt returns the integer value of a dotted key path in the config
+// file. Floating point values are accepted if they are whole numbers,
+// as JSON decodes all numbers as float64.
+func (c *Context) GetInt(key string) (int, error) {
+	v, err := c.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := toInt(v)
+	if !ok {
+		return 0, fmt.Errorf("key %s is not an integer but %T", key, v)
+	}
+	return n, nil
+}
+
+// GetFloat64 returns the numeric value of a dotted key path in the
+// config file.
+func (c *Context) GetFloat64(key string) (float64, error) {
+	v, err := c.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := toFloat64(v)
+	if !ok {
+		return 0, fmt.Errorf("key %s is not a number but %T", key, v)
+	}
+	return n, nil
+}
+
+// toInt converts numbers of any width to int, e.g. the int8 values of
+// MessagePack. Floating point values must be whole numbers.
+func toInt(v interface{}) (int, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := rv.Int()
+		return int(n), int64(int(n)) == n
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n := rv.Uint()
+		return int(n), n <= math.MaxInt64 && int64(int(n)) == int64(n)
+	case reflect.Float32, reflect.Float64:
+		n := rv.Float()
+		ok := n == math.Trunc(n) && n >= math.MinInt64 && n < math.MaxInt64 && float64(int(n)) == n
+		return int(n), ok
+	}
+	return 0, false
+}
+
+// toFloat64 converts numbers of any width to float64.
+func toFloat64(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// GetBool returns the boolean value of a dotted key path in the config
+// file.
+func (c *Context) GetBool(key string) (bool, error) {
+	v, err := c.Get(key)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("key %s is not a bool but %T", key, v)
+	}
+	return b, nil
+}
+
+// Set sets the value of a dotted key path like "Sub.Field" in the config
+// file and writes it back. Missing intermediate objects are created, but
+// array indices must be in range.
+func (c *Context) Set(key string, value interface{}) error {
+	unlock, err := c.lock()
+	if err != nil {
+		return c.wrapErr("write", err)
+	}
+	defer unlock()
+	tree, err := c.readTree()
+	if err != nil {
+		return c.wrapErr("read", err)
+	}
+	if err := setKey(tree, key, value); err != nil {
+		return c.wrapErr("write", err)
+	}
+	data, err := c.marshal(tree)
+	if err != nil {
+		return c.wrapErr("write", err)
+	}
+	return c.wrapErr("write", c.writeFile(data))
+}
+
+// GetPointer returns the value of a JSON Pointer (RFC 6901) like
+// "/Sub/Field" in the config file. In keys, "~1" stands for "/" and "~0"
+// for "~". The empty pointer returns the whole config. It returns an
+// error wrapping ErrNoKey if the pointer cannot be resolved.
+func (c *Context) GetPointer(ptr string) (interface{}, error) {
+	if ptr != "" && !strings.HasPrefix(ptr, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must start with /", ptr)
+	}
+	unlock, err := c.rlock()
+	if err != nil {
+		return nil, c.wrapErr("read", err)
+	}
+	defer unlock()
+	tree, err := c.readTree()
+	if err != nil {
+		return nil, c.wrapErr("read", err)
+	}
+	if ptr == "" {
+		return tree, nil
+	}
+	unescape := strings.NewReplacer("~1", "/", "~0", "~")
+	parts := strings.Split(ptr[1:], "/")
+	for i, part := range parts {
+		parts[i] = unescape.Replace(part)
+	}
+	return lookupParts(tree, ptr, parts)
+}
+
+func lookupKey(tree interface{}, key string) (interface{}, error) {
+	return lookupParts(tree, key, strings.Split(key, "."))
+}
+
+// lookupParts returns the value of the path of parts in tree, where key
+// is the original path for errors.
+func lookupParts(tree interface{}, key string, parts []string) (interface{}, error) {
+	v := tree
+	for _, part := range parts {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if v, ok = node[part]; !ok {
+				return nil, fmt.Errorf("%w: %s", ErrNoKey, key)
+			}
+		case []interface{}:
+			i, err := arrayIndex(node, key, part)
+			if err != nil {
+				return nil, err
+			}
+			v = node[i]
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrNoKey, key)
+		}
+	}
+	return v, nil
+}
+
+func setKey(tree interface{}, key string, value interface{}) error {
+	parts := strings.Split(key, ".")
+	v := tree
+	for i, part := range parts {
+		last := i == len(parts)-1
+		switch node := v.(type) {
+		case map[string]interface{}:
+			if last {
+				node[part] = value
+				return nil
+			}
+			next, ok := node[part]
+			if !ok {
+				next = make(map[string]interface{})
+				node[part] = next
+			}
+			v = next
+		case []interface{}:
+			j, err := arrayIndex(node, key, part)
+			if err != nil {
+				return err
+			}
+			if last {
+				node[j] = value
+				return nil
+			}
+			if node[j] == nil {
+				node[j] = make(map[string]interface{})
+			}
+			v = node[j]
+		default:
+			return fmt.Errorf("key %s: %s is not an object or array", key, strings.Join(parts[:i], "."))
+		}
+	}
+	return nil
+}
+
+// arrayIndex returns the index of array selected by part of key.
+func arrayIndex(array []interface{}, key, part string) (int, error) {
+	i, err := strconv.Atoi(part)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s: %s is not an array index", ErrNoKey, key, part)
+	}
+	if i < 0 || i >= len(array) {
+		return 0, fmt.Errorf("%w: %s: index %d out of range [0:%d]", ErrNoKey, key, i, len(array))
+	}
+	return i, nil
+}
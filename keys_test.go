@@ -0,0 +1,235 @@
+package conf
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGetSet(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := conf.GetString("Sub.Field")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "test" {
+		t.Errorf("Unexpected value: %s", s)
+	}
+	if _, err := conf.GetString("Sub.Missing"); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Expected ErrNoKey, got %v", err)
+	}
+	if _, err := conf.GetString("Number"); err == nil {
+		t.Error("Expected error for non-string value")
+	}
+
+	// Set an existing and a new nested key
+	if err := conf.Set("Sub.Field", "changed"); err != nil {
+		t.Fatal(err)
+	}
+	if err := conf.Set("New.Nested.Field", "new"); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := conf.GetString("New.Nested.Field"); s != "new" {
+		t.Errorf("Unexpected value: %s", s)
+	}
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Sub.Field = "changed"
+	if cfg != cfgRead {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+
+	var cerr *ConfigError
+	if err := conf.Set("String.Field", "invalid"); !errors.As(err, &cerr) || cerr.Op != "write" {
+		t.Errorf("Expected write ConfigError when setting a key below a non-object, got %v", err)
+	}
+}
+
+func TestGetSetCBOR(t *testing.T) {
+	// CBOR decodes objects into map[interface{}]interface{}
+	conf := Build().Directory(t.TempDir()).CBOR().Create()
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	for key, expected := range map[string]string{"String": "Just testing", "Sub.Field": "test"} {
+		if s, err := conf.GetString(key); err != nil || s != expected {
+			t.Errorf("%s: Unexpected value: %q, %v", key, s, err)
+		}
+	}
+	if v, err := conf.GetPointer("/Sub/Field"); err != nil || v != "test" {
+		t.Errorf("Unexpected value: %v, %v", v, err)
+	}
+
+	if err := conf.Set("Sub.Field", "changed"); err != nil {
+		t.Fatal(err)
+	}
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Sub.Field = "changed"
+	if cfg != cfgRead {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+}
+
+func TestTypedGetters(t *testing.T) {
+	for _, b := range []*Builder{Build().JSON(), Build().YAML(), Build().MsgPack(), Build().CBOR()} {
+		conf := b.Directory(t.TempDir()).Create()
+		cfg := map[string]interface{}{
+			"Int":   42,
+			"Float": 1.5,
+			"Bool":  true,
+			"Str":   "text",
+		}
+		if err := conf.Write(cfg); err != nil {
+			t.Fatal(err)
+		}
+
+		if n, err := conf.GetInt("Int"); err != nil || n != 42 {
+			t.Errorf("%s: GetInt: %v, %v", conf.Format(), n, err)
+		}
+		if f, err := conf.GetFloat64("Float"); err != nil || f != 1.5 {
+			t.Errorf("%s: GetFloat64: %v, %v", conf.Format(), f, err)
+		}
+		if f, err := conf.GetFloat64("Int"); err != nil || f != 42 {
+			t.Errorf("%s: GetFloat64: %v, %v", conf.Format(), f, err)
+		}
+		if v, err := conf.GetBool("Bool"); err != nil || !v {
+			t.Errorf("%s: GetBool: %v, %v", conf.Format(), v, err)
+		}
+
+		// Conversion errors
+		if _, err := conf.GetInt("Float"); err == nil {
+			t.Errorf("%s: expected error for GetInt on a fraction", conf.Format())
+		}
+		if _, err := conf.GetInt("Str"); err == nil {
+			t.Errorf("%s: expected error for GetInt on a string", conf.Format())
+		}
+		if _, err := conf.GetFloat64("Bool"); err == nil {
+			t.Errorf("%s: expected error for GetFloat64 on a bool", conf.Format())
+		}
+		if _, err := conf.GetBool("Int"); err == nil {
+			t.Errorf("%s: expected error for GetBool on an int", conf.Format())
+		}
+		if _, err := conf.GetBool("Missing"); !errors.Is(err, ErrNoKey) {
+			t.Errorf("%s: expected ErrNoKey, got %v", conf.Format(), err)
+		}
+	}
+}
+
+func TestGetSetArray(t *testing.T) {
+	// Initialize config context with an array-rooted config
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+	cfg := []TestConfig{
+		{"first", 1, struct{ Field string }{"a"}},
+		{"second", 2, struct{ Field string }{"b"}},
+	}
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := conf.GetString("1.Sub.Field")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "b" {
+		t.Errorf("Unexpected value: %s", s)
+	}
+	if _, err := conf.Get("2.String"); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Expected ErrNoKey for out-of-range index, got %v", err)
+	}
+	if _, err := conf.Get("first"); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Expected ErrNoKey for non-numeric index, got %v", err)
+	}
+
+	if err := conf.Set("0.String", "changed"); err != nil {
+		t.Fatal(err)
+	}
+	if err := conf.Set("5.String", "invalid"); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Expected ErrNoKey for out-of-range index, got %v", err)
+	}
+
+	var cfgRead []TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	cfg[0].String = "changed"
+	if len(cfgRead) != 2 || cfgRead[0] != cfg[0] || cfgRead[1] != cfg[1] {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+}
+
+func TestReadMap(t *testing.T) {
+	conf := Build().Directory("testdata").JSON().Create()
+	m, err := conf.ReadMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"String": "Just testing",
+		"Number": float64(123),
+		"Sub": map[string]interface{}{
+			"Field": "test",
+		},
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("Maps differ: %v, %v", expected, m)
+	}
+
+	// A missing file is an empty map
+	conf = Build().Directory(t.TempDir()).JSON().Create()
+	if m, err = conf.ReadMap(); err != nil {
+		t.Fatal(err)
+	}
+	if m == nil || len(m) != 0 {
+		t.Errorf("Expected empty map, got %v", m)
+	}
+}
+
+func TestGetPointer(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+	data := `{"Sub": {"Field": "test", "a/b": {"m~n": 1}}, "List": [{"Name": "first"}]}`
+	if err := conf.WriteBytes([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	for ptr, expected := range map[string]interface{}{
+		"/Sub/Field":     "test",
+		"/Sub/a~1b/m~0n": float64(1),
+		"/List/0/Name":   "first",
+	} {
+		v, err := conf.GetPointer(ptr)
+		if err != nil {
+			t.Errorf("%s: %v", ptr, err)
+		} else if v != expected {
+			t.Errorf("%s: Unexpected value: %v", ptr, v)
+		}
+	}
+
+	root, err := conf.GetPointer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := root.(map[string]interface{}); !ok {
+		t.Errorf("Expected whole config, got %v", root)
+	}
+
+	for _, ptr := range []string{"/Sub/Missing", "/List/1/Name", "/Sub/Field/x"} {
+		if _, err := conf.GetPointer(ptr); !errors.Is(err, ErrNoKey) {
+			t.Errorf("%s: Expected ErrNoKey, got %v", ptr, err)
+		}
+	}
+	if _, err := conf.GetPointer("Sub/Field"); err == nil {
+		t.Error("Expected error for pointer without leading /")
+	}
+}
@@ -0,0 +1,75 @@
+package conf
+
+import (
+	"bytes"
+)
+
+// NormalizeLineEndings makes Write convert all line endings to LF and
+// Read convert CRLF and CR to LF before decoding, so config files edited
+// on both Windows and Unix produce clean diffs. Use LineEnding to write
+// another ending.
+//
+// Only the text formats that escape carriage returns in values are
+// normalized. INI and CSV write them unescaped, so their line endings are
+// left unchanged, as are those of binary and custom encodings.
+func (b *Builder) NormalizeLineEndings() *Builder {
+	if b.ctx.LineEnding == "" {
+		b.ctx.LineEnding = "\n"
+	}
+	b.ctx.NormalizeRead = true
+	return b
+}
+
+// LineEnding sets the line ending that Write converts all line endings
+// to, e.g. "\r\n" for config files that are only edited on Windows.
+func (b *Builder) LineEnding(ending string) *Builder {
+	b.ctx.LineEnding = ending
+	return b
+}
+
+// lineFormats are the text formats that escape carriage returns in
+// values, so all carriage returns in their encoding are line endings.
+var lineFormats = map[Format]bool{
+	FormatJSON:       true,
+	FormatJSONC:      true,
+	FormatYAML:       true,
+	FormatTOML:       true,
+	FormatXML:        true,
+	FormatDotEnv:     true,
+	FormatHCL:        true,
+	FormatProperties: true,
+	FormatProtoText:  true,
+}
+
+// normalizes reports whether the line endings of the encoding of the
+// context can be normalized.
+func (c *Context) normalizes() bool {
+	return lineFormats[c.format]
+}
+
+// toLF converts CRLF and CR line endings to LF.
+func toLF(data []byte) []byte {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	return bytes.ReplaceAll(data, []byte("\r"), []byte("\n"))
+}
+
+// normalizeWrite converts the line endings of encoded data to the line
+// ending of the context.
+func (c *Context) normalizeWrite(data []byte) []byte {
+	if c.LineEnding == "" || !c.normalizes() {
+		return data
+	}
+	data = toLF(data)
+	if c.LineEnding == "\n" {
+		return data
+	}
+	return bytes.ReplaceAll(data, []byte("\n"), []byte(c.LineEnding))
+}
+
+// normalizeRead converts the line endings of encoded data to LF.
+func (c *Context) normalizeRead(data []byte) []byte {
+	if !c.NormalizeRead || !c.normalizes() {
+		return data
+	}
+	return toLF(data)
+}
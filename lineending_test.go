@@ -0,0 +1,89 @@
+package conf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNormalizeLineEndings(t *testing.T) {
+	// Initialize config context with a header containing CRLF
+	conf := Build().Directory(t.TempDir()).YAML().Header("line one\r\nline two").NormalizeLineEndings().Create()
+	cfg := TestConfig{"Just\r\ntesting", 123, struct{ Field string }{"test"}}
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+	data, err := conf.ReadBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("\r")) {
+		t.Errorf("Expected only LF line endings: %q", data)
+	}
+
+	// Read normalizes CRLF written by another editor
+	crlf := bytes.ReplaceAll(data, []byte("\n"), []byte("\r\n"))
+	if err := conf.WriteBytes(crlf); err != nil {
+		t.Fatal(err)
+	}
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfg != cfgRead {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+
+	// A configured ending is used instead
+	conf = Build().Directory(t.TempDir()).YAML().NormalizeLineEndings().LineEnding("\r\n").Create()
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if data, err = conf.ReadBytes(); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Count(data, []byte("\n")) != bytes.Count(data, []byte("\r\n")) {
+		t.Errorf("Expected only CRLF line endings: %q", data)
+	}
+
+	// INI writes carriage returns in values unescaped, which are kept
+	conf = Build().Directory(t.TempDir()).INI().NormalizeLineEndings().Create()
+	cfg.String = "a\r\nb\rc"
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfgRead = TestConfig{}
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfg != cfgRead {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+
+	// WriteStream falls back to Write to convert line endings
+	conf = Build().Directory(t.TempDir()).JSON().LineEnding("\r\n").Create()
+	if err := conf.WriteStream(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if data, err = conf.ReadBytes(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(data, []byte("\r\n")) {
+		t.Errorf("Expected CRLF line endings: %q", data)
+	}
+
+	// Custom encodings are left unchanged
+	raw := []byte("a\r\nb\rc")
+	conf = Build().Directory(t.TempDir()).Marshaller(
+		func(v interface{}) ([]byte, error) { return raw, nil },
+		func(data []byte, v interface{}) error { return nil },
+	).NormalizeLineEndings().Create()
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if data, err = conf.ReadBytes(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, raw) {
+		t.Errorf("Expected unchanged encoding: %q", data)
+	}
+}
@@ -0,0 +1,79 @@
+package conf
+
+import (
+	"errors"
+	"os"
+)
+
+// FileLock enables advisory locking of a ".lock" file next to the config
+// file while it is read or written, which serializes access to the config
+// file across processes.
+func (b *Builder) FileLock() *Builder {
+	b.ctx.FileLock = true
+	return b
+}
+
+// lock acquires the exclusive lock of the context for writing. The
+// returned function releases it. As every write acquires this lock, it
+// fails with ErrReadOnly for read-only contexts.
+func (c *Context) lock() (unlock func(), err error) {
+	if c.ReadOnly {
+		return nil, ErrReadOnly
+	}
+	c.mu.Lock()
+	if !c.FileLock || c.stdio() {
+		return c.mu.Unlock, nil
+	}
+	release, err := c.lockFile(true)
+	if err != nil {
+		c.mu.Unlock()
+		return nil, err
+	}
+	return func() {
+		release()
+		c.mu.Unlock()
+	}, nil
+}
+
+// rlock acquires the shared lock of the context for reading. The
+// returned function releases it.
+func (c *Context) rlock() (unlock func(), err error) {
+	c.mu.RLock()
+	if !c.FileLock || c.stdio() {
+		return c.mu.RUnlock, nil
+	}
+	release, err := c.lockFile(false)
+	if err != nil {
+		c.mu.RUnlock()
+		return nil, err
+	}
+	return func() {
+		release()
+		c.mu.RUnlock()
+	}, nil
+}
+
+// lockFile locks the lock file of the config file. For shared locks, a
+// missing config directory is not locked, as there is nothing to read.
+func (c *Context) lockFile(exclusive bool) (release func(), err error) {
+	if exclusive && !c.NoCreateDir {
+		if err := os.MkdirAll(c.Directory, c.dirMode()); err != nil {
+			return nil, err
+		}
+	}
+	f, err := os.OpenFile(c.Path()+".lock", os.O_RDWR|os.O_CREATE, c.fileMode())
+	if !exclusive && errors.Is(err, os.ErrNotExist) {
+		return func() {}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := flock(f, exclusive); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() {
+		funlock(f)
+		f.Close()
+	}, nil
+}
@@ -0,0 +1,18 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package conf
+
+import (
+	"errors"
+	"os"
+)
+
+var errLockUnsupported = errors.New("File locking is not supported on this platform")
+
+func flock(f *os.File, exclusive bool) error {
+	return errLockUnsupported
+}
+
+func funlock(f *os.File) error {
+	return errLockUnsupported
+}
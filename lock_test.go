@@ -0,0 +1,33 @@
+package conf
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestFileLock(t *testing.T) {
+	dir := t.TempDir()
+
+	// Separate contexts for the same file simulate separate processes
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := 0; i < 2; i++ {
+		conf := Build().Directory(dir).JSON().FileLock().Create()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				errs <- conf.Write(TestConfig{Number: j})
+				var cfgRead TestConfig
+				errs <- conf.Read(&cfgRead)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
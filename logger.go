@@ -0,0 +1,28 @@
+package conf
+
+// LogFunc receives debug events of a context, e.g. "read.start", with
+// alternating keys and values like "path" and the path of the file.
+type LogFunc func(event string, kv ...interface{})
+
+// Logger sets a function that is called with debug events while reading
+// and writing, e.g. to find out which file was read or whether a fallback
+// was taken. It can be adapted to any structured logger, e.g. slog:
+//
+//	conf.Build().Logger(func(event string, kv ...interface{}) {
+//		slog.Debug(event, kv...)
+//	})
+//
+// The events are "read.start", "read.missing", "read.fallback",
+// "read.embedded", "read.done", "write.start", "write.backup",
+// "write.rename" and "write.done".
+func (b *Builder) Logger(fn LogFunc) *Builder {
+	b.ctx.Logger = fn
+	return b
+}
+
+// log calls the logger of the context, if any.
+func (c *Context) log(event string, kv ...interface{}) {
+	if c.Logger != nil {
+		c.Logger(event, kv...)
+	}
+}
@@ -0,0 +1,38 @@
+package conf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	// Initialize config context that records events
+	var events []string
+	var last []interface{}
+	conf := Build().Directory(t.TempDir()).JSON().Logger(func(event string, kv ...interface{}) {
+		events = append(events, event)
+		last = kv
+	}).Create()
+
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"read.start", "read.missing", "read.done"}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("Unexpected events: %v", events)
+	}
+
+	events = nil
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+	expected = []string{"write.start", "write.rename", "write.done"}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("Unexpected events: %v", events)
+	}
+	if len(last) != 6 || last[0] != "path" || last[1] != conf.Path() || last[2] != "bytes" {
+		t.Errorf("Unexpected key/values: %v", last)
+	}
+}
@@ -0,0 +1,107 @@
+package conf
+
+import (
+	"reflect"
+)
+
+// Merge reads the config file into a new value of the type pointed to by
+// conf and copies all of its non-zero fields to conf. Nested structs are
+// merged field by field and map entries are added to the existing map.
+// This keeps values already set in conf unless the config file sets them.
+//
+// Note that a field explicitly set to its zero value in the config file,
+// e.g. 0, false or "", cannot override a value in conf.
+func (c *Context) Merge(conf interface{}) error {
+	v := reflect.ValueOf(conf)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return ErrNotPointer
+	}
+	unlock, err := c.rlock()
+	if err != nil {
+		return c.wrapErr("read", err)
+	}
+	defer unlock()
+	fresh := reflect.New(v.Elem().Type())
+	if err := c.decodeFile(c.Path(), fresh.Interface()); err != nil {
+		return c.wrapErr("read", err)
+	}
+	mergeValues(v.Elem(), fresh.Elem())
+	return nil
+}
+
+// mergeValues copies all non-zero values of src into dst.
+func mergeValues(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Struct:
+		for i := 0; i < src.NumField(); i++ {
+			if dst.Field(i).CanSet() {
+				mergeValues(dst.Field(i), src.Field(i))
+			}
+		}
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMap(src.Type()))
+		}
+		iter := src.MapRange()
+		for iter.Next() {
+			dst.SetMapIndex(iter.Key(), iter.Value())
+		}
+	default:
+		if !src.IsZero() {
+			dst.Set(src)
+		}
+	}
+}
+
+// SliceMerge selects how ReadMerged merges slices.
+type SliceMerge int
+
+const (
+	// MergeReplace replaces slices of earlier files by those of later ones.
+	MergeReplace SliceMerge = iota
+	// MergeAppend appends slices of later files to those of earlier ones.
+	MergeAppend
+)
+
+// MergeSlices sets how ReadMerged merges slices, defaults to MergeReplace.
+func (b *Builder) MergeSlices(mode SliceMerge) *Builder {
+	b.ctx.MergeSlices = mode
+	return b
+}
+
+// deepMerge merges src into dst. Nested maps are merged recursively, and
+// slices are merged according to mode. Other values of src override
+// those of dst.
+func deepMerge(dst, src map[string]interface{}, mode SliceMerge) {
+	for k, sv := range src {
+		dst[k] = mergeValue(dst[k], sv, mode)
+	}
+}
+
+// mergeValue returns the merge of src into dst. Besides string-keyed maps
+// it merges the map[interface{}]interface{} values some decoders produce
+// for nested objects, e.g. CBOR.
+func mergeValue(dst, src interface{}, mode SliceMerge) interface{} {
+	switch s := src.(type) {
+	case map[string]interface{}:
+		if d, ok := dst.(map[string]interface{}); ok {
+			deepMerge(d, s, mode)
+			return d
+		}
+	case map[interface{}]interface{}:
+		if d, ok := dst.(map[interface{}]interface{}); ok {
+			for k, sv := range s {
+				d[k] = mergeValue(d[k], sv, mode)
+			}
+			return d
+		}
+	case []interface{}:
+		if d, ok := dst.([]interface{}); ok && mode == MergeAppend {
+			return append(d, s...)
+		}
+	}
+	return src
+}
@@ -0,0 +1,163 @@
+package conf
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type MergeTestConfig struct {
+	String string
+	Number int
+	Sub    struct {
+		Field string
+		Other string
+	}
+	Map map[string]string
+}
+
+func TestMerge(t *testing.T) {
+	dir := t.TempDir()
+	conf := Build().Directory(dir).JSON().Create()
+	data := `{"Number": 5, "Sub": {"Field": "file"}, "Map": {"b": "file"}}`
+	if err := ioutil.WriteFile(filepath.Join(dir, "config.json"), []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Pre-set values are kept unless the file overrides them
+	var cfg MergeTestConfig
+	cfg.String = "default"
+	cfg.Number = 1
+	cfg.Sub.Field = "default"
+	cfg.Sub.Other = "default"
+	cfg.Map = map[string]string{"a": "default", "b": "default"}
+	if err := conf.Merge(&cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.String != "default" || cfg.Number != 5 {
+		t.Errorf("Unexpected config: %v", cfg)
+	}
+	if cfg.Sub.Field != "file" || cfg.Sub.Other != "default" {
+		t.Errorf("Unexpected nested config: %v", cfg.Sub)
+	}
+	if cfg.Map["a"] != "default" || cfg.Map["b"] != "file" {
+		t.Errorf("Unexpected map: %v", cfg.Map)
+	}
+
+	if err := conf.Merge(cfg); err != ErrNotPointer {
+		t.Errorf("Expected ErrNotPointer, got %v", err)
+	}
+}
+
+type LayerTestConfig struct {
+	Name   string
+	Server struct {
+		Host string
+		Port int
+		TLS  struct {
+			Cert string
+			Key  string
+		}
+	}
+	Tags []string
+}
+
+func TestReadMergedLayers(t *testing.T) {
+	// Initialize three layers of config files
+	system, user, local := t.TempDir(), t.TempDir(), t.TempDir()
+	layers := map[string]string{
+		system: `{"Name": "system", "Server": {"Host": "0.0.0.0", "Port": 80, "TLS": {"Cert": "system.crt", "Key": "system.key"}}, "Tags": ["a"]}`,
+		user:   `{"Server": {"Port": 8080, "TLS": {"Cert": "user.crt"}}, "Tags": ["b"]}`,
+		local:  `{"Name": "local", "Server": {"TLS": {"Key": "local.key"}}}`,
+	}
+	for dir, data := range layers {
+		if err := ioutil.WriteFile(filepath.Join(dir, "config.json"), []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var expected LayerTestConfig
+	expected.Name = "local"
+	expected.Server.Host = "0.0.0.0"
+	expected.Server.Port = 8080
+	expected.Server.TLS.Cert = "user.crt"
+	expected.Server.TLS.Key = "local.key"
+
+	conf := Build().Directory(local).SearchPaths(system, user, local).JSON().Create()
+	var cfgRead LayerTestConfig
+	if err := conf.ReadMerged(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	expected.Tags = []string{"b"}
+	if !reflect.DeepEqual(cfgRead, expected) {
+		t.Errorf("Configs differ: %v, %v", expected, cfgRead)
+	}
+
+	conf = Build().Directory(local).SearchPaths(system, user, local).MergeSlices(MergeAppend).JSON().Create()
+	cfgRead = LayerTestConfig{}
+	if err := conf.ReadMerged(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	expected.Tags = []string{"a", "b"}
+	if !reflect.DeepEqual(cfgRead, expected) {
+		t.Errorf("Configs differ: %v, %v", expected, cfgRead)
+	}
+}
+
+func TestReadMergedFormats(t *testing.T) {
+	var expected LayerTestConfig
+	expected.Name = "local"
+	expected.Server.Host = "0.0.0.0"
+	expected.Server.Port = 8080
+	expected.Server.TLS.Cert = "user.crt"
+	expected.Server.TLS.Key = "local.key"
+
+	// CBOR decodes nested objects into map[interface{}]interface{}
+	system, user, local := t.TempDir(), t.TempDir(), t.TempDir()
+	layers := map[string]map[string]interface{}{
+		system: {"Name": "system", "Server": map[string]interface{}{"Host": "0.0.0.0", "Port": 80, "TLS": map[string]interface{}{"Cert": "system.crt", "Key": "system.key"}}},
+		user:   {"Server": map[string]interface{}{"Port": 8080, "TLS": map[string]interface{}{"Cert": "user.crt"}}},
+		local:  {"Name": "local", "Server": map[string]interface{}{"TLS": map[string]interface{}{"Key": "local.key"}}},
+	}
+	for dir, layer := range layers {
+		if err := Build().Directory(dir).CBOR().Create().Write(layer); err != nil {
+			t.Fatal(err)
+		}
+	}
+	conf := Build().Directory(local).SearchPaths(system, user, local).CBOR().Create()
+	var cfgRead LayerTestConfig
+	if err := conf.ReadMerged(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfgRead, expected) {
+		t.Errorf("Configs differ: %v, %v", expected, cfgRead)
+	}
+
+	// Properties cannot decode into maps, so each file is decoded in turn
+	system, user = t.TempDir(), t.TempDir()
+	files := map[string]string{
+		system: "app.name=system\nserver.host=0.0.0.0\nserver.port=80\n",
+		user:   "server.port=8080\ndebug=true\n",
+	}
+	for dir, data := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, "config.properties"), []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var expectedProps PropertiesTestConfig
+	expectedProps.Name = "system"
+	expectedProps.Server.Host = "0.0.0.0"
+	expectedProps.Server.Port = 8080
+	expectedProps.Debug = true
+
+	conf = Build().Directory(user).SearchPaths(system, user).Properties().Create()
+	var propsRead PropertiesTestConfig
+	if err := conf.ReadMerged(&propsRead); err != nil {
+		t.Fatal(err)
+	}
+	if propsRead != expectedProps {
+		t.Errorf("Configs differ: %v, %v", expectedProps, propsRead)
+	}
+}
@@ -0,0 +1,25 @@
+package conf
+
+import (
+	"time"
+)
+
+// MetricsFunc is called at the end of each read or write with its
+// duration, the size of the config file in bytes and the resulting error.
+type MetricsFunc func(dur time.Duration, size int, err error)
+
+// Metrics sets functions that observe each Read and Write, including
+// failed ones, e.g. to export them to a monitoring system. Either may be
+// nil. The duration includes retries, but not waiting for locks.
+func (b *Builder) Metrics(onRead, onWrite MetricsFunc) *Builder {
+	b.ctx.ReadMetrics = onRead
+	b.ctx.WriteMetrics = onWrite
+	return b
+}
+
+// observe calls fn with the duration since start, if fn is set.
+func observe(fn MetricsFunc, start time.Time, size int, err error) {
+	if fn != nil {
+		fn(time.Since(start), size, err)
+	}
+}
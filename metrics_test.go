@@ -0,0 +1,63 @@
+package conf
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+type metric struct {
+	dur  time.Duration
+	size int
+	err  error
+}
+
+func TestMetrics(t *testing.T) {
+	// Initialize config context that records metrics
+	var reads, writes []metric
+	conf := Build().Directory(t.TempDir()).JSON().Metrics(
+		func(dur time.Duration, size int, err error) {
+			reads = append(reads, metric{dur, size, err})
+		},
+		func(dur time.Duration, size int, err error) {
+			writes = append(writes, metric{dur, size, err})
+		},
+	).Create()
+
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+	data, err := conf.ReadBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(writes) != 1 || writes[0].size != len(data) || writes[0].dur < 0 || writes[0].err != nil {
+		t.Errorf("Unexpected write metrics: %v", writes)
+	}
+
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if len(reads) != 1 || reads[0].size != len(data) || reads[0].dur < 0 || reads[0].err != nil {
+		t.Errorf("Unexpected read metrics: %v", reads)
+	}
+
+	// Failed operations are observed as well
+	if err := ioutil.WriteFile(conf.Path(), []byte("invalid"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := conf.Read(&cfgRead); err == nil {
+		t.Fatal("Expected error for invalid config")
+	}
+	if len(reads) != 2 || reads[1].size != len("invalid") || reads[1].err == nil {
+		t.Errorf("Unexpected read metrics: %v", reads)
+	}
+	if err := conf.Write(func() {}); err == nil {
+		t.Fatal("Expected error for unencodable config")
+	}
+	if len(writes) != 2 || writes[1].size != 0 || writes[1].err == nil {
+		t.Errorf("Unexpected write metrics: %v", writes)
+	}
+}
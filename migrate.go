@@ -0,0 +1,115 @@
+package conf
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// VersionKey is the key of the schema version stored by migrations.
+const VersionKey = "_version"
+
+// Migrations sets functions that upgrade old config files on Read. The
+// config is decoded into a map, and the migrations run on it before it is
+// decoded into the actual config value. The migration at index i upgrades
+// the config from version i to i+1, where the version is stored under
+// VersionKey and defaults to 0, so only the missing migrations run.
+//
+// Write stores the current version under VersionKey, by re-encoding the
+// config as a map, so written configs are not migrated again. Read only
+// passes VersionKey on to maps and structs with a field for it, e.g.
+// `json:"_version"`. The encoding must be able to decode into maps.
+func (b *Builder) Migrations(migrations ...func(m map[string]interface{}) error) *Builder {
+	b.ctx.Migrations = migrations
+	return b
+}
+
+// migrate runs the missing migrations on the encoded config data and
+// returns the encoding of the upgraded config for conf.
+func (c *Context) migrate(data []byte, conf interface{}) ([]byte, error) {
+	if c.Marshal == nil {
+		return nil, ErrNoMarshal
+	}
+	var m map[string]interface{}
+	if err := c.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	version, err := configVersion(m)
+	if err != nil {
+		return nil, err
+	}
+	_, stored := m[VersionKey]
+	keep := hasVersionField(conf)
+	if version >= len(c.Migrations) && (keep || !stored) {
+		return data, nil
+	}
+	if version < len(c.Migrations) {
+		for i, migration := range c.Migrations[version:] {
+			if err := migration(m); err != nil {
+				return nil, fmt.Errorf("migration to version %d: %w", version+i+1, err)
+			}
+		}
+	}
+	m[VersionKey] = len(c.Migrations)
+	if !keep {
+		delete(m, VersionKey)
+	}
+	return c.Marshal(m)
+}
+
+// stampVersion stores the current version under VersionKey in the encoded
+// config data.
+func (c *Context) stampVersion(data []byte) ([]byte, error) {
+	if c.Unmarshal == nil {
+		return nil, ErrNoUnmarshal
+	}
+	var m map[string]interface{}
+	if err := c.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	if version, err := configVersion(m); err == nil && version == len(c.Migrations) {
+		return data, nil
+	}
+	m[VersionKey] = len(c.Migrations)
+	return c.Marshal(m)
+}
+
+// versionTags are the struct tags that may name a field VersionKey.
+var versionTags = []string{"json", "yaml", "toml", "xml", "msgpack", "cbor", "conf"}
+
+// hasVersionField reports whether conf points to a map or to a struct
+// with a field for VersionKey.
+func hasVersionField(conf interface{}) bool {
+	t := reflect.TypeOf(conf)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Map:
+		return true
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			for _, tag := range versionTags {
+				if strings.Split(t.Field(i).Tag.Get(tag), ",")[0] == VersionKey {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// configVersion returns the version stored in the config map.
+func configVersion(m map[string]interface{}) (int, error) {
+	v, ok := m[VersionKey]
+	if !ok || v == nil {
+		return 0, nil
+	}
+	if n, ok := toInt(v); ok && n >= 0 {
+		return n, nil
+	}
+	return 0, fmt.Errorf("invalid config version %v", v)
+}
@@ -0,0 +1,151 @@
+package conf
+
+import (
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+type MigrateTestConfig struct {
+	Version int `json:"_version"`
+	Name    string
+	Port    int
+}
+
+func TestMigrations(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Migrations(
+		// v1: rename Title to Name
+		func(m map[string]interface{}) error {
+			m["Name"] = m["Title"]
+			delete(m, "Title")
+			return nil
+		},
+		// v2: set a default Port
+		func(m map[string]interface{}) error {
+			if _, ok := m["Port"]; !ok {
+				m["Port"] = 80
+			}
+			return nil
+		},
+	).Create()
+
+	// A config without version runs all migrations
+	if err := ioutil.WriteFile(conf.Path(), []byte(`{"Title": "old"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	var cfgRead MigrateTestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	expected := MigrateTestConfig{2, "old", 80}
+	if cfgRead != expected {
+		t.Errorf("Configs differ: %v, %v", expected, cfgRead)
+	}
+
+	// A config at version 1 only runs the second migration
+	if err := ioutil.WriteFile(conf.Path(), []byte(`{"_version": 1, "Title": "ignored", "Name": "new"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfgRead = MigrateTestConfig{}
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	expected = MigrateTestConfig{2, "new", 80}
+	if cfgRead != expected {
+		t.Errorf("Configs differ: %v, %v", expected, cfgRead)
+	}
+}
+
+func TestMigrationError(t *testing.T) {
+	errMigration := errors.New("migration failed")
+	conf := Build().Directory(t.TempDir()).JSON().Migrations(func(m map[string]interface{}) error {
+		return errMigration
+	}).Create()
+	if err := ioutil.WriteFile(conf.Path(), []byte(`{}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfgRead MigrateTestConfig
+	if err := conf.Read(&cfgRead); !errors.Is(err, errMigration) {
+		t.Errorf("Expected migration error, got %v", err)
+	}
+}
+
+func TestMigrationsWithoutVersionField(t *testing.T) {
+	type Config struct {
+		Name string
+	}
+	conf := Build().Directory(t.TempDir()).JSON().Strict().Migrations(
+		// v1: rename Title to Name
+		func(m map[string]interface{}) error {
+			m["Name"] = m["Title"]
+			delete(m, "Title")
+			return nil
+		},
+	).Create()
+
+	if err := ioutil.WriteFile(conf.Path(), []byte(`{"Title": "old"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	var cfgRead Config
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead.Name != "old" {
+		t.Errorf("Unexpected name: %q", cfgRead.Name)
+	}
+
+	// Write stores the version, so the migration does not run again
+	if err := conf.Write(cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	data, err := conf.ReadBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"_version": 1`) {
+		t.Errorf("Expected version in config: %s", data)
+	}
+	cfgRead = Config{}
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead.Name != "old" {
+		t.Errorf("Unexpected name after round trip: %q", cfgRead.Name)
+	}
+
+	// So does WriteStream
+	if err := conf.WriteStream(cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	cfgRead = Config{}
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead.Name != "old" {
+		t.Errorf("Unexpected name after streaming: %q", cfgRead.Name)
+	}
+}
+
+func TestMigrationsMsgPack(t *testing.T) {
+	// MessagePack decodes small versions as int8
+	calls := 0
+	conf := Build().Directory(t.TempDir()).MsgPack().Migrations(func(m map[string]interface{}) error {
+		calls++
+		return nil
+	}).Create()
+	if err := conf.Write(MigrateTestConfig{Name: "test"}); err != nil {
+		t.Fatal(err)
+	}
+	var cfgRead MigrateTestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no migration, got %d", calls)
+	}
+	if cfgRead.Name != "test" {
+		t.Errorf("Unexpected name: %q", cfgRead.Name)
+	}
+}
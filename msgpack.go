@@ -0,0 +1,13 @@
+package conf
+
+import (
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// MsgPack sets the encoding to the binary MessagePack format.
+func (b *Builder) MsgPack() *Builder {
+	if b.ctx.File == "" {
+		b.ctx.File = "config.msgpack"
+	}
+	return b.codec(FormatMsgPack, msgpack.Marshal, msgpack.Unmarshal)
+}
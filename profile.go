@@ -0,0 +1,47 @@
+package conf
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Profile sets the profile of the config file, e.g. "prod", which is
+// inserted into the file name before its extension: config.prod.json.
+// Read falls back to the file without profile if the profile specific
+// file does not exist, while Write always writes the profile specific
+// file. An empty profile disables profiles, so the profile can be taken
+// from the environment:
+//
+//	conf.Build().App("myapp").Profile(os.Getenv("APP_ENV")).JSON().Create()
+func (b *Builder) Profile(profile string) *Builder {
+	b.ctx.Profile = profile
+	return b
+}
+
+// fileName returns the name of the config file including the profile.
+func (c *Context) fileName() string {
+	if c.Profile == "" {
+		return c.File
+	}
+	base, gz := c.File, ""
+	if strings.HasSuffix(base, ".gz") {
+		base, gz = strings.TrimSuffix(base, ".gz"), ".gz"
+	}
+	ext := filepath.Ext(base)
+	return strings.TrimSuffix(base, ext) + "." + c.Profile + ext + gz
+}
+
+// readPath returns the path of the config file to read, which is the
+// file without profile if the profile specific file does not exist.
+func (c *Context) readPath() string {
+	path := c.Path()
+	if c.Profile == "" {
+		return path
+	}
+	if c.missing(path) {
+		fallback := filepath.Join(c.Directory, c.File)
+		c.log("read.fallback", "path", path, "fallback", fallback)
+		return fallback
+	}
+	return path
+}
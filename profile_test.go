@@ -0,0 +1,58 @@
+package conf
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestProfile(t *testing.T) {
+	dir := t.TempDir()
+	base := Build().Directory(dir).JSON().Create()
+	prod := Build().Directory(dir).JSON().Profile("prod").Create()
+	if prod.Path() != filepath.Join(dir, "config.prod.json") {
+		t.Errorf("Unexpected path: %s", prod.Path())
+	}
+
+	// Falls back to the base config
+	if err := base.Write(TestConfig{String: "base"}); err != nil {
+		t.Fatal(err)
+	}
+	var cfgRead TestConfig
+	if err := prod.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead.String != "base" {
+		t.Errorf("Unexpected config: %v", cfgRead)
+	}
+
+	// Prefers the profile specific config
+	if err := prod.Write(TestConfig{String: "prod"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := prod.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead.String != "prod" {
+		t.Errorf("Unexpected config: %v", cfgRead)
+	}
+	if err := base.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead.String != "base" {
+		t.Errorf("Base config was overwritten: %v", cfgRead)
+	}
+}
+
+func TestProfileFileName(t *testing.T) {
+	tests := map[string]string{
+		"config.json":    "config.dev.json",
+		"config":         "config.dev",
+		"config.json.gz": "config.dev.json.gz",
+	}
+	for file, expected := range tests {
+		conf := Build().File(file).Profile("dev").Create()
+		if name := conf.fileName(); name != expected {
+			t.Errorf("%s: expected %s, got %s", file, expected, name)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package conf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+func propertiesMarshal(v interface{}) ([]byte, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil, ErrNotStruct
+	}
+	var buf bytes.Buffer
+	writeProperties(&buf, rv, "")
+	return buf.Bytes(), nil
+}
+
+// writeProperties writes the fields of the struct v as key=value lines,
+// with the keys of nested structs joined by dots.
+func writeProperties(buf *bytes.Buffer, v reflect.Value, prefix string) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key, ok := fieldKey(t.Field(i))
+		if !ok {
+			continue
+		}
+		key = prefix + key
+		if t.Field(i).Type.Kind() == reflect.Struct {
+			writeProperties(buf, v.Field(i), key+".")
+			continue
+		}
+		s := fmt.Sprint(v.Field(i).Interface())
+		fmt.Fprintf(buf, "%s=%s\n", escapeProperty(key, true), escapeProperty(s, false))
+	}
+}
+
+// escapeProperty escapes s as a key or value of a properties file.
+// Non-ASCII characters are escaped, as properties files are Latin-1.
+func escapeProperty(s string, key bool) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\f':
+			b.WriteString(`\f`)
+		case r == ' ' && (key || i == 0):
+			b.WriteString(`\ `)
+		case (r == '=' || r == ':') && key, (r == '#' || r == '!') && i == 0:
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			for _, c := range utf16.Encode([]rune{r}) {
+				fmt.Fprintf(&b, `\u%04x`, c)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func propertiesUnmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return ErrNotStruct
+	}
+	fields := make(map[string]reflect.Value)
+	propertyFields(fields, rv.Elem(), "")
+
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimLeft(s.Text(), " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		// Lines ending in an odd number of backslashes continue on the
+		// next line, without its leading whitespace.
+		for continued(line) && s.Scan() {
+			n++
+			line = line[:len(line)-1] + strings.TrimLeft(s.Text(), " \t\f")
+		}
+		key, value, err := splitProperty(line)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", n, err)
+		}
+		field, ok := fields[key]
+		if !ok {
+			continue
+		}
+		if err := setValue(field, value); err != nil {
+			return fmt.Errorf("line %d: %s: %v", n, key, err)
+		}
+	}
+	return s.Err()
+}
+
+// propertyFields adds the fields of the struct v to fields by their
+// dotted keys.
+func propertyFields(fields map[string]reflect.Value, v reflect.Value, prefix string) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key, ok := fieldKey(t.Field(i))
+		if !ok {
+			continue
+		}
+		if t.Field(i).Type.Kind() == reflect.Struct {
+			propertyFields(fields, v.Field(i), prefix+key+".")
+		} else {
+			fields[prefix+key] = v.Field(i)
+		}
+	}
+}
+
+// continued reports whether line ends in an unescaped backslash.
+func continued(line string) bool {
+	n := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// splitProperty splits a logical line into its unescaped key and value.
+// The key ends at the first unescaped '=', ':' or whitespace.
+func splitProperty(line string) (key, value string, err error) {
+	end := len(line)
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+		} else if strings.IndexByte("=: \t\f", line[i]) >= 0 {
+			end = i
+			break
+		}
+	}
+	rest := strings.TrimLeft(line[end:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+	if key, err = unescapeProperty(line[:end]); err != nil {
+		return "", "", err
+	}
+	value, err = unescapeProperty(rest)
+	return key, value, err
+}
+
+// unescapeProperty reverts escapeProperty.
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var b strings.Builder
+	var surrogates []uint16
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		if s[i] == 'u' {
+			if i+5 > len(s) {
+				return "", fmt.Errorf("invalid escape %q", s[i-1:])
+			}
+			c, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("invalid escape %q", s[i-1:i+5])
+			}
+			i += 4
+			surrogates = append(surrogates, uint16(c))
+			if !utf16.IsSurrogate(rune(c)) || len(surrogates) == 2 {
+				b.WriteString(string(utf16.Decode(surrogates)))
+				surrogates = surrogates[:0]
+			}
+			continue
+		}
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'f':
+			b.WriteByte('\f')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// Properties sets the encoding to Java properties files of key=value
+// lines. Fields of nested structs have dotted keys, e.g. "server.port".
+// The key of a field is its name, or the name given in its conf tag.
+func (b *Builder) Properties() *Builder {
+	if b.ctx.File == "" {
+		b.ctx.File = "config.properties"
+	}
+	return b.codec(FormatProperties, propertiesMarshal, propertiesUnmarshal)
+}
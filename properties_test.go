@@ -0,0 +1,73 @@
+package conf
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+type PropertiesTestConfig struct {
+	Name   string `conf:"app.name"`
+	Server struct {
+		Host string `conf:"host"`
+		Port int    `conf:"port"`
+	} `conf:"server"`
+	Debug bool `conf:"debug"`
+}
+
+func TestProperties(t *testing.T) {
+	// Initialize config context
+	conf := Build().Directory(t.TempDir()).Properties().Create()
+	if conf.File != "config.properties" {
+		t.Errorf("Unexpected file name: %s", conf.File)
+	}
+
+	// Example config with a value that needs escaping
+	var cfg PropertiesTestConfig
+	cfg.Name = " a=b: c\\d\nünï"
+	cfg.Server.Host = "localhost"
+	cfg.Server.Port = 8080
+	cfg.Debug = true
+
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+	data, err := conf.ReadBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "server.port=8080\n") {
+		t.Errorf("Expected nested key: %s", data)
+	}
+
+	var cfgRead PropertiesTestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfg != cfgRead {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+}
+
+func TestPropertiesSyntax(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).Properties().Create()
+	data := `# A comment
+! Another comment
+app.name : multi \
+           line
+server.host   localhost
+server.port=80
+app\:weird\ key = ignored
+`
+	if err := ioutil.WriteFile(conf.Path(), []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfgRead PropertiesTestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead.Name != "multi line" || cfgRead.Server.Host != "localhost" || cfgRead.Server.Port != 80 {
+		t.Errorf("Unexpected config: %v", cfgRead)
+	}
+}
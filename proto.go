@@ -0,0 +1,53 @@
+package conf
+
+import (
+	"errors"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+)
+
+var ErrNotProto = errors.New("Config is not a proto.Message")
+
+// protoCodec adapts the marshal and unmarshal functions of a protobuf
+// encoding to values of type interface{}.
+func protoCodec(
+	marshal func(proto.Message) ([]byte, error),
+	unmarshal func([]byte, proto.Message) error,
+) (MarshalFunc, UnmarshalFunc) {
+	m := func(v interface{}) ([]byte, error) {
+		msg, ok := v.(proto.Message)
+		if !ok {
+			return nil, ErrNotProto
+		}
+		return marshal(msg)
+	}
+	u := func(data []byte, v interface{}) error {
+		msg, ok := v.(proto.Message)
+		if !ok {
+			return ErrNotProto
+		}
+		return unmarshal(data, msg)
+	}
+	return m, u
+}
+
+// Proto sets the encoding to the binary Protocol Buffers format. The
+// config must be a proto.Message, usually a pointer to a generated struct.
+func (b *Builder) Proto() *Builder {
+	if b.ctx.File == "" {
+		b.ctx.File = "config.pb"
+	}
+	m, u := protoCodec(proto.Marshal, proto.Unmarshal)
+	return b.codec(FormatProto, m, u)
+}
+
+// ProtoText sets the encoding to the human-readable Protocol Buffers text
+// format. The config must be a proto.Message.
+func (b *Builder) ProtoText() *Builder {
+	if b.ctx.File == "" {
+		b.ctx.File = "config.txtpb"
+	}
+	m, u := protoCodec(prototext.MarshalOptions{Multiline: true}.Marshal, prototext.Unmarshal)
+	return b.codec(FormatProtoText, m, u)
+}
@@ -0,0 +1,34 @@
+package conf
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/apipb"
+)
+
+func TestProto(t *testing.T) {
+	for _, b := range []*Builder{Build().Proto(), Build().ProtoText()} {
+		// Initialize config context
+		conf := b.Directory(t.TempDir()).Create()
+
+		// Example config, a small generated message
+		cfg := &apipb.Api{Name: "Just testing", Version: "v1"}
+
+		if err := conf.Write(cfg); err != nil {
+			t.Fatal(err)
+		}
+		cfgRead := &apipb.Api{}
+		if err := conf.Read(cfgRead); err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(cfg, cfgRead) {
+			t.Errorf("%s: Configs differ: %v, %v", conf.Format(), cfg, cfgRead)
+		}
+
+		if err := conf.Write(TestConfig{}); !errors.Is(err, ErrNotProto) {
+			t.Errorf("%s: Expected ErrNotProto, got %v", conf.Format(), err)
+		}
+	}
+}
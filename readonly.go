@@ -0,0 +1,17 @@
+package conf
+
+import (
+	"errors"
+)
+
+// ErrReadOnly is returned by all methods that modify the config file of a
+// read-only context.
+var ErrReadOnly = errors.New("Context is read-only")
+
+// ReadOnly forbids writing the config file, e.g. if it is managed by an
+// operator. Write and all other methods that modify the config file
+// return ErrReadOnly without touching the file system.
+func (b *Builder) ReadOnly() *Builder {
+	b.ctx.ReadOnly = true
+	return b
+}
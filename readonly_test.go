@@ -0,0 +1,45 @@
+package conf
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"testing"
+)
+
+func TestReadOnly(t *testing.T) {
+	// Initialize config context
+	conf := Build().Directory(t.TempDir()).JSON().ReadOnly().Create()
+	original := []byte(`{"String": "managed"}`)
+	if err := ioutil.WriteFile(conf.Path(), original, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if err := conf.Write(cfg); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly, got %v", err)
+	}
+	if err := conf.WriteBytes([]byte("{}")); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly, got %v", err)
+	}
+	if err := conf.Delete(); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly, got %v", err)
+	}
+
+	data, err := ioutil.ReadFile(conf.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, original) {
+		t.Errorf("Expected file to be untouched: %s", data)
+	}
+
+	// Reading is still allowed
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead.String != "managed" {
+		t.Errorf("Unexpected config: %v", cfgRead)
+	}
+}
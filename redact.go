@@ -0,0 +1,86 @@
+package conf
+
+import (
+	"reflect"
+)
+
+// Redacted is the value that replaces secret fields in MarshalRedacted.
+const Redacted = "***"
+
+// MarshalRedacted returns the encoding of conf like Preview, but with the
+// values of all struct fields tagged with `conf:",secret"` redacted.
+// Secret string fields are replaced by Redacted, other secret fields are
+// set to their zero value. conf itself is not modified.
+func (c *Context) MarshalRedacted(conf interface{}) ([]byte, error) {
+	if c.Marshal == nil {
+		return nil, ErrNoMarshal
+	}
+	if conf == nil {
+		return c.Marshal(conf)
+	}
+	return c.Marshal(redact(reflect.ValueOf(conf)).Interface())
+}
+
+// redact returns a copy of v with all secret struct fields redacted,
+// including those of structs in slices, arrays, maps and interfaces.
+func redact(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		p := reflect.New(v.Elem().Type())
+		p.Elem().Set(redact(v.Elem()))
+		return p
+	case reflect.Struct:
+		r := reflect.New(v.Type()).Elem()
+		r.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			if !hasTagOption(field, "secret") {
+				r.Field(i).Set(redact(v.Field(i)))
+			} else if field.Type.Kind() == reflect.String {
+				r.Field(i).SetString(Redacted)
+			} else {
+				r.Field(i).Set(reflect.Zero(field.Type))
+			}
+		}
+		return r
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		r := reflect.New(v.Type()).Elem()
+		r.Set(redact(v.Elem()))
+		return r
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		r := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			r.Index(i).Set(redact(v.Index(i)))
+		}
+		return r
+	case reflect.Array:
+		r := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			r.Index(i).Set(redact(v.Index(i)))
+		}
+		return r
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		r := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			r.SetMapIndex(iter.Key(), redact(iter.Value()))
+		}
+		return r
+	}
+	return v
+}
@@ -0,0 +1,91 @@
+package conf
+
+import (
+	"bytes"
+	"testing"
+)
+
+type SecretTestConfig struct {
+	User     string
+	Password string `conf:",secret"`
+	Sub      struct {
+		Token string `conf:",secret"`
+		PIN   int    `conf:",secret"`
+	}
+}
+
+func TestMarshalRedacted(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+
+	var cfg SecretTestConfig
+	cfg.User = "user"
+	cfg.Password = "hunter2"
+	cfg.Sub.Token = "abcdef"
+	cfg.Sub.PIN = 1234
+
+	data, err := conf.MarshalRedacted(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"hunter2", "abcdef", "1234"} {
+		if bytes.Contains(data, []byte(secret)) {
+			t.Errorf("Secret %q was not redacted:\n%s", secret, data)
+		}
+	}
+	if !bytes.Contains(data, []byte("user")) || !bytes.Contains(data, []byte(Redacted)) {
+		t.Errorf("Unexpected redacted config:\n%s", data)
+	}
+	if cfg.Password != "hunter2" {
+		t.Error("MarshalRedacted modified the config")
+	}
+
+	// Write keeps the actual values
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+	var cfgRead SecretTestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead != cfg {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+}
+
+func TestMarshalRedactedNested(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+
+	type Account struct {
+		User     string
+		Password string `conf:",secret"`
+	}
+	cfg := struct {
+		List  []Account
+		Array [1]Account
+		Map   map[string]Account
+		Any   interface{}
+	}{
+		List:  []Account{{"list", "secret1"}},
+		Array: [1]Account{{"array", "secret2"}},
+		Map:   map[string]Account{"key": {"map", "secret3"}},
+		Any:   Account{"any", "secret4"},
+	}
+
+	data, err := conf.MarshalRedacted(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"secret1", "secret2", "secret3", "secret4"} {
+		if bytes.Contains(data, []byte(secret)) {
+			t.Errorf("Secret %q was not redacted:\n%s", secret, data)
+		}
+	}
+	for _, user := range []string{"list", "array", "map", "any"} {
+		if !bytes.Contains(data, []byte(user)) {
+			t.Errorf("Expected user %q:\n%s", user, data)
+		}
+	}
+	if cfg.List[0].Password != "secret1" || cfg.Map["key"].Password != "secret3" {
+		t.Error("MarshalRedacted modified the config")
+	}
+}
@@ -0,0 +1,68 @@
+package conf
+
+import (
+	"os"
+	"sort"
+	"sync"
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]*Context{}
+)
+
+// Register adds the context c to the package-level registry under the
+// given name, replacing any context registered under that name before.
+// Registered contexts are reported by Diagnose.
+func Register(name string, c *Context) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = c
+}
+
+// Registered returns a copy of the registry, mapping names to contexts.
+func Registered() map[string]*Context {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	contexts := make(map[string]*Context, len(registry))
+	for name, c := range registry {
+		contexts[name] = c
+	}
+	return contexts
+}
+
+// Diagnostic describes the config file of a registered context.
+type Diagnostic struct {
+	Name   string
+	Path   string
+	Exists bool
+	Format Format
+	Size   int64
+	Err    error
+}
+
+// Diagnose returns a diagnostic for each registered context, sorted by
+// name, e.g. to print them in a "doctor" command. Err is set if the config
+// file exists but cannot be accessed.
+func Diagnose() []Diagnostic {
+	contexts := Registered()
+	names := make([]string, 0, len(contexts))
+	for name := range contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	diags := make([]Diagnostic, 0, len(names))
+	for _, name := range names {
+		c := contexts[name]
+		d := Diagnostic{Name: name, Path: c.Path(), Format: c.Format()}
+		fi, err := c.fsys().Stat(d.Path)
+		if err == nil {
+			d.Exists, d.Size = true, fi.Size()
+		} else if !os.IsNotExist(err) {
+			d.Err = err
+		}
+		diags = append(diags, d)
+	}
+	return diags
+}
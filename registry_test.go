@@ -0,0 +1,41 @@
+package conf
+
+import (
+	"testing"
+)
+
+func TestDiagnose(t *testing.T) {
+	// Initialize config contexts
+	dir := t.TempDir()
+	app := Build().Directory(dir).JSON().Create()
+	cache := Build().Directory(dir).YAML().Create()
+	Register("app", app)
+	Register("cache", cache)
+	defer func() {
+		registryMu.Lock()
+		delete(registry, "app")
+		delete(registry, "cache")
+		registryMu.Unlock()
+	}()
+
+	if r := Registered(); r["app"] != app || r["cache"] != cache {
+		t.Errorf("Unexpected registry: %v", r)
+	}
+
+	if err := app.WriteBytes([]byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+	diags := Diagnose()
+	if len(diags) != 2 {
+		t.Fatalf("Expected 2 diagnostics, got %v", diags)
+	}
+	expected := []Diagnostic{
+		{Name: "app", Path: app.Path(), Exists: true, Format: FormatJSON, Size: 2},
+		{Name: "cache", Path: cache.Path(), Exists: false, Format: FormatYAML},
+	}
+	for i, d := range diags {
+		if d != expected[i] {
+			t.Errorf("Diagnostics differ: %v, %v", expected[i], d)
+		}
+	}
+}
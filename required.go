@@ -0,0 +1,58 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrRequired is returned by Read if fields tagged with `conf:",required"`
+// are left at their zero value. The error lists the dotted paths of all
+// missing fields, e.g. "missing required fields: Name, Sub.Field".
+var ErrRequired = errors.New("missing required fields")
+
+// checkRequired returns an ErrRequired error listing the missing required
+// fields of the struct pointed to by conf. Other values have none.
+func checkRequired(conf interface{}) error {
+	v := reflect.ValueOf(conf)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil
+	}
+	missing := requiredFields(v.Elem(), "", nil)
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrRequired, strings.Join(missing, ", "))
+}
+
+// requiredFields appends the paths of the missing required fields of v,
+// prefixed by path, to missing.
+func requiredFields(v reflect.Value, path string, missing []string) []string {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return missing
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return missing
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key, ok := fieldKey(field)
+		if !ok {
+			continue
+		}
+		if path != "" {
+			key = path + "." + key
+		}
+		if hasTagOption(field, "required") && v.Field(i).IsZero() {
+			missing = append(missing, key)
+			continue
+		}
+		missing = requiredFields(v.Field(i), key, missing)
+	}
+	return missing
+}
@@ -0,0 +1,41 @@
+package conf
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+)
+
+type RequiredTestConfig struct {
+	Name string `conf:",required"`
+	Port int    `conf:"port,required"`
+	Sub  struct {
+		Field string `conf:",required"`
+	}
+}
+
+func TestRequired(t *testing.T) {
+	// Initialize config context
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+	if err := ioutil.WriteFile(conf.Path(), []byte(`{"Port": 80}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfgRead RequiredTestConfig
+	err := conf.Read(&cfgRead)
+	if !errors.Is(err, ErrRequired) {
+		t.Fatalf("Expected ErrRequired, got %v", err)
+	}
+	expected := "read " + conf.Path() + ": missing required fields: Name, Sub.Field"
+	if err.Error() != expected {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	// A complete config is read without error
+	if err := ioutil.WriteFile(conf.Path(), []byte(`{"Name": "test", "Port": 80, "Sub": {"Field": "test"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+}
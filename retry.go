@@ -0,0 +1,50 @@
+package conf
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// Retry makes Read and Write retry on transient errors, e.g. on network
+// file systems, for up to attempts attempts in total. The backoff is the
+// wait before the first retry and doubles with each further retry.
+// Errors are transient if they are EAGAIN, EINTR or timeouts; a missing
+// config file is never retried.
+func (b *Builder) Retry(attempts int, backoff time.Duration) *Builder {
+	b.ctx.RetryAttempts = attempts
+	b.ctx.RetryBackoff = backoff
+	return b
+}
+
+// retry calls fn until it succeeds, fails with a permanent error, or the
+// retry attempts of the context are exhausted.
+func (c *Context) retry(fn func() error) error {
+	backoff := c.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= c.RetryAttempts || !transient(err) {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// timeout is implemented by errors that report timeouts, e.g. net.Error
+// and os.PathError.
+type timeout interface {
+	Timeout() bool
+}
+
+// transient reports whether err may go away when retried.
+func transient(err error) bool {
+	if errors.Is(err, os.ErrNotExist) {
+		return false
+	}
+	if transientErrno(err) {
+		return true
+	}
+	var t timeout
+	return errors.As(err, &t) && t.Timeout()
+}
@@ -0,0 +1,13 @@
+//go:build !plan9
+
+package conf
+
+import (
+	"errors"
+	"syscall"
+)
+
+// transientErrno reports whether err is EAGAIN or EINTR.
+func transientErrno(err error) bool {
+	return errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EINTR)
+}
@@ -0,0 +1,21 @@
+//go:build !plan9
+
+package conf
+
+import (
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestTransientErrno(t *testing.T) {
+	for _, errno := range []syscall.Errno{syscall.EAGAIN, syscall.EINTR} {
+		err := &os.PathError{Op: "read", Path: "config.json", Err: errno}
+		if !transient(err) {
+			t.Errorf("Expected %v to be transient", errno)
+		}
+	}
+	if transient(&os.PathError{Op: "read", Path: "config.json", Err: syscall.EACCES}) {
+		t.Error("Expected EACCES to be permanent")
+	}
+}
@@ -0,0 +1,9 @@
+//go:build plan9
+
+package conf
+
+// transientErrno reports whether err is EAGAIN or EINTR, which do not
+// exist on Plan 9.
+func transientErrno(err error) bool {
+	return false
+}
@@ -0,0 +1,100 @@
+package conf
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+// timeoutError is a transient error like those of network file systems.
+type timeoutError struct{}
+
+func (timeoutError) Error() string { return "i/o timeout" }
+func (timeoutError) Timeout() bool { return true }
+
+// failing returns a read or write hook that fails with err for the first n calls.
+func failing(n int, err error) (hook func(data []byte) ([]byte, error), calls *int) {
+	calls = new(int)
+	return func(data []byte) ([]byte, error) {
+		*calls++
+		if *calls <= n {
+			return nil, err
+		}
+		return data, nil
+	}, calls
+}
+
+func TestRetryWrite(t *testing.T) {
+	// Initialize config context
+	hook, calls := failing(2, &os.PathError{Op: "write", Path: "config.json", Err: timeoutError{}})
+	conf := Build().Directory(t.TempDir()).JSON().OnWrite(hook).Retry(3, time.Millisecond).Create()
+
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if *calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", *calls)
+	}
+
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead != cfg {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+}
+
+func TestRetryRead(t *testing.T) {
+	// Initialize config context
+	dir := t.TempDir()
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if err := Build().Directory(dir).JSON().Create().Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+	hook, calls := failing(1, timeoutError{})
+	conf := Build().Directory(dir).JSON().OnRead(hook).Retry(2, time.Millisecond).Create()
+
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if *calls != 2 {
+		t.Errorf("Expected 2 attempts, got %d", *calls)
+	}
+	if cfgRead != cfg {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+
+	// Attempts are limited
+	hook, calls = failing(5, timeoutError{})
+	conf = Build().Directory(dir).JSON().OnRead(hook).Retry(3, time.Millisecond).Create()
+	if err := conf.Read(&cfgRead); !errors.As(err, new(timeoutError)) {
+		t.Errorf("Expected timeout error, got %v", err)
+	}
+	if *calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", *calls)
+	}
+}
+
+func TestRetryPermanent(t *testing.T) {
+	errPermanent := errors.New("permanent")
+	hook, calls := failing(5, errPermanent)
+	conf := Build().Directory(t.TempDir()).JSON().OnRead(hook).Retry(3, time.Millisecond).Create()
+	if err := conf.WriteBytes([]byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); !errors.Is(err, errPermanent) {
+		t.Errorf("Expected permanent error, got %v", err)
+	}
+	if *calls != 1 {
+		t.Errorf("Expected 1 attempt, got %d", *calls)
+	}
+	if transient(os.ErrNotExist) {
+		t.Error("Expected ErrNotExist not to be transient")
+	}
+}
@@ -0,0 +1,98 @@
+package conf
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// SchemaDraft is the JSON Schema version of the schemas returned by Schema.
+const SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema returns a JSON Schema describing the struct conf, or the struct
+// pointed to by conf, which editors can use to validate and complete
+// config files. Properties are named like encoding/json names them, and
+// fields tagged with `conf:",required"` are listed as required.
+func (c *Context) Schema(conf interface{}) ([]byte, error) {
+	t := reflect.TypeOf(conf)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, ErrNotStruct
+	}
+	schema := typeSchema(t, map[reflect.Type]bool{})
+	schema["$schema"] = SchemaDraft
+	return json.MarshalIndent(schema, "", "    ")
+}
+
+// typeSchema returns the schema of values of type t. Types in seen are
+// currently being described, so recursive types end in a plain object.
+func typeSchema(t reflect.Type, seen map[reflect.Type]bool) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string"}
+		}
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem(), seen)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem(), seen)}
+	case reflect.Struct:
+		schema := map[string]interface{}{"type": "object"}
+		if seen[t] {
+			return schema
+		}
+		seen[t] = true
+		defer delete(seen, t)
+		properties := map[string]interface{}{}
+		var required []string
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			key, ok := jsonKey(field)
+			if !ok {
+				continue
+			}
+			properties[key] = typeSchema(field.Type, seen)
+			if hasTagOption(field, "required") {
+				required = append(required, key)
+			}
+		}
+		schema["properties"] = properties
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	}
+	return map[string]interface{}{}
+}
+
+// jsonKey returns the key of a struct field as encoded by encoding/json.
+// ok is false if the field is not encoded.
+func jsonKey(field reflect.StructField) (key string, ok bool) {
+	if field.PkgPath != "" {
+		return "", false
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	if i := strings.Index(tag, ","); i >= 0 {
+		tag = tag[:i]
+	}
+	if tag != "" {
+		return tag, true
+	}
+	return field.Name, true
+}
@@ -0,0 +1,76 @@
+package conf
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type SchemaTestConfig struct {
+	Name  string `json:"name" conf:",required"`
+	Ports []int
+	Sub   struct {
+		Field string `conf:",required"`
+	}
+}
+
+func TestSchema(t *testing.T) {
+	conf := Build().JSON().Create()
+
+	data, err := conf.Schema(TestConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema struct {
+		Schema     string `json:"$schema"`
+		Type       string
+		Properties map[string]struct {
+			Type       string
+			Properties map[string]struct{ Type string }
+		}
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if schema.Schema != SchemaDraft || schema.Type != "object" {
+		t.Errorf("Unexpected schema: %s", data)
+	}
+	if schema.Properties["Number"].Type != "integer" {
+		t.Errorf("Expected integer Number: %s", data)
+	}
+	if schema.Properties["Sub"].Properties["Field"].Type != "string" {
+		t.Errorf("Expected string Sub.Field: %s", data)
+	}
+}
+
+func TestSchemaRequired(t *testing.T) {
+	conf := Build().JSON().Create()
+
+	data, err := conf.Schema(&SchemaTestConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema struct {
+		Required   []string
+		Properties map[string]struct {
+			Type     string
+			Items    struct{ Type string }
+			Required []string
+		}
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if len(schema.Required) != 1 || schema.Required[0] != "name" {
+		t.Errorf("Expected required name: %s", data)
+	}
+	if schema.Properties["Ports"].Items.Type != "integer" {
+		t.Errorf("Expected integer Ports items: %s", data)
+	}
+	if req := schema.Properties["Sub"].Required; len(req) != 1 || req[0] != "Field" {
+		t.Errorf("Expected required Sub.Field: %s", data)
+	}
+
+	if _, err := conf.Schema("not a struct"); err != ErrNotStruct {
+		t.Errorf("Expected ErrNotStruct, got %v", err)
+	}
+}
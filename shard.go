@@ -0,0 +1,90 @@
+package conf
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Shard splits the config into several files in the directory of the
+// context, which are read and written as one struct by ReadSharded and
+// WriteSharded. The map assigns each top-level struct field name to the
+// file storing that field, e.g. {"Server": "server.json"}.
+func (b *Builder) Shard(shards map[string]string) *Builder {
+	b.ctx.Shards = shards
+	return b
+}
+
+// ReadSharded reads each shard file into its field of the struct pointed
+// to by conf. Fields of missing shard files keep their zero or default
+// value. If the context has a validator, it is called with the whole
+// struct afterwards.
+func (c *Context) ReadSharded(conf interface{}) error {
+	v := reflect.ValueOf(conf)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return c.wrapErr("read", ErrNotStruct)
+	}
+	if c.Defaults != nil {
+		if err := c.applyDefaults(conf, c.Defaults); err != nil {
+			return c.wrapErr("read", err)
+		}
+	}
+	for _, name := range c.shardNames() {
+		field, err := c.shardField(v.Elem(), name)
+		if err != nil {
+			return c.wrapErr("read", err)
+		}
+		if err := c.shard(name).Read(field.Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	return c.wrapErr("read", c.validate(conf))
+}
+
+// WriteSharded writes each shard field of the struct conf, or the struct
+// pointed to by conf, into its own file.
+func (c *Context) WriteSharded(conf interface{}) error {
+	v := reflect.Indirect(reflect.ValueOf(conf))
+	if v.Kind() != reflect.Struct {
+		return c.wrapErr("write", ErrNotStruct)
+	}
+	for _, name := range c.shardNames() {
+		field, err := c.shardField(v, name)
+		if err != nil {
+			return c.wrapErr("write", err)
+		}
+		if err := c.shard(name).Write(field.Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shardNames returns the sorted field names of the shards.
+func (c *Context) shardNames() []string {
+	names := make([]string, 0, len(c.Shards))
+	for name := range c.Shards {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// shardField returns the field of the struct v with the given name.
+func (c *Context) shardField(v reflect.Value, name string) (reflect.Value, error) {
+	field := v.FieldByName(name)
+	if !field.IsValid() {
+		return field, fmt.Errorf("shard field %s not found in %s", name, v.Type())
+	}
+	return field, nil
+}
+
+// shard returns the context of the shard file for the given field. The
+// defaults and validator apply to the whole struct, not to its fields.
+func (c *Context) shard(name string) *Context {
+	shard := c.WithFile(c.Shards[name])
+	shard.Defaults = nil
+	shard.Validator = nil
+	shard.Shards = nil
+	return shard
+}
@@ -0,0 +1,70 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type ShardTestConfig struct {
+	Server struct {
+		Host string
+		Port int
+	}
+	Logging struct {
+		Level string
+	}
+}
+
+func TestSharded(t *testing.T) {
+	// Initialize config context
+	dir := t.TempDir()
+	conf := Build().Directory(dir).JSON().Shard(map[string]string{
+		"Server":  "server.json",
+		"Logging": "logging.json",
+	}).Create()
+
+	var cfg ShardTestConfig
+	cfg.Server.Host = "localhost"
+	cfg.Server.Port = 8080
+	cfg.Logging.Level = "debug"
+	if err := conf.WriteSharded(cfg); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{"server.json", "logging.json"} {
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			t.Errorf("Expected shard file %s: %v", file, err)
+		}
+	}
+
+	var cfgRead ShardTestConfig
+	if err := conf.ReadSharded(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead != cfg {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+
+	// A missing shard file leaves its field zero
+	if err := os.Remove(filepath.Join(dir, "logging.json")); err != nil {
+		t.Fatal(err)
+	}
+	cfgRead = ShardTestConfig{}
+	if err := conf.ReadSharded(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	expected := cfg
+	expected.Logging.Level = ""
+	if cfgRead != expected {
+		t.Errorf("Configs differ: %v, %v", expected, cfgRead)
+	}
+}
+
+func TestShardUnknownField(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Shard(map[string]string{
+		"Database": "db.json",
+	}).Create()
+	if err := conf.WriteSharded(ShardTestConfig{}); err == nil {
+		t.Error("Expected error for unknown shard field")
+	}
+}
@@ -0,0 +1,25 @@
+package conf
+
+import (
+	"errors"
+)
+
+// ErrStdio is returned by methods that need an actual config file, like
+// Delete and Edit, for contexts that use Stdio.
+var ErrStdio = errors.New("Config file is standard input or output")
+
+// Stdio is the file name that makes Read decode the config from standard
+// input and Write encode it to standard output, like many Unix tools do:
+//
+//	conf.Build().File(conf.Stdio).JSON().Create()
+//
+// The directory of the context is ignored, so no directories or lock
+// files are created. Exists always reports true and Touch does nothing,
+// while Delete and Edit return ErrStdio.
+const Stdio = "-"
+
+// stdio reports whether the context reads from standard input and writes
+// to standard output.
+func (c *Context) stdio() bool {
+	return c.File == Stdio
+}
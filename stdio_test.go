@@ -0,0 +1,83 @@
+package conf
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestStdio(t *testing.T) {
+	// Initialize config context in an empty directory
+	dir := t.TempDir()
+	conf := Build().Directory(dir).File(Stdio).JSON().FileLock().Create()
+
+	// Read config from a pipe as stdin
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = stdin }()
+	if _, err := w.WriteString(`{"String": "Just testing", "Number": 123}`); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	r.Close()
+	if cfgRead.String != "Just testing" || cfgRead.Number != 123 {
+		t.Errorf("Unexpected config: %v", cfgRead)
+	}
+
+	// Write config to a pipe as stdout
+	r, w, err = os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	if err := conf.Write(cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := conf.Preview(cfgRead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(expected) {
+		t.Errorf("Unexpected output: %s", data)
+	}
+
+	// File operations do not act on a file named Stdio
+	if exists, err := conf.Exists(); err != nil || !exists {
+		t.Errorf("Expected Exists to report true: %v", err)
+	}
+	if err := conf.Touch(); err != nil {
+		t.Fatal(err)
+	}
+	if err := conf.Delete(); !errors.Is(err, ErrStdio) {
+		t.Errorf("Expected ErrStdio, got %v", err)
+	}
+	if err := conf.Edit(); !errors.Is(err, ErrStdio) {
+		t.Errorf("Expected ErrStdio, got %v", err)
+	}
+
+	// Nothing was written to the directory
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Errorf("Expected empty directory, got %d files", len(files))
+	}
+}
@@ -0,0 +1,47 @@
+package conf
+
+import (
+	"bufio"
+	"io"
+)
+
+// StreamEncoder writes encoded values to an underlying stream,
+// e.g. json.Encoder.
+type StreamEncoder interface {
+	Encode(v interface{}) error
+}
+
+// StreamEncoder sets the function that creates stream encoders for
+// WriteStream. It is set automatically for JSON.
+func (b *Builder) StreamEncoder(fn func(w io.Writer) StreamEncoder) *Builder {
+	b.ctx.NewEncoder = fn
+	return b
+}
+
+// WriteStream writes conf into the config file like Write, but encodes it
+// directly into the file instead of buffering the whole encoding in
+// memory. This requires a stream encoder, which is only available for
+// JSON by default. For other encodings, for types that implement
+// ConfMarshaler, or if compression, encryption, a header, a line ending,
+// migrations or an OnWrite hook is enabled, WriteStream falls back to
+// Write.
+func (c *Context) WriteStream(conf interface{}) error {
+	if _, ok := conf.(ConfMarshaler); ok {
+		return c.Write(conf)
+	}
+	if c.NewEncoder == nil || c.Compressed || c.EncryptionKey != nil || c.Header != "" || c.LineEnding != "" || len(c.Migrations) > 0 || c.OnWrite != nil {
+		return c.Write(conf)
+	}
+	unlock, err := c.lock()
+	if err != nil {
+		return c.wrapErr("write", err)
+	}
+	defer unlock()
+	return c.wrapErr("write", c.writeAtomic(func(w io.Writer) error {
+		bw := bufio.NewWriter(w)
+		if err := c.NewEncoder(bw).Encode(conf); err != nil {
+			return err
+		}
+		return bw.Flush()
+	}))
+}
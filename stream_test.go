@@ -0,0 +1,43 @@
+package conf
+
+import (
+	"testing"
+)
+
+func TestWriteStream(t *testing.T) {
+	for _, b := range []*Builder{Build().JSON(), Build().YAML()} {
+		conf := b.Directory(t.TempDir()).Create()
+
+		large := make([]int, 100000)
+		for i := range large {
+			large[i] = i
+		}
+		if err := conf.WriteStream(large); err != nil {
+			t.Fatal(err)
+		}
+
+		var largeRead []int
+		if err := conf.Read(&largeRead); err != nil {
+			t.Fatal(err)
+		}
+		if len(largeRead) != len(large) || largeRead[len(large)-1] != len(large)-1 {
+			t.Errorf("%s: unexpected config of length %d", conf.Format(), len(largeRead))
+		}
+	}
+}
+
+func TestWriteStreamConfMarshaler(t *testing.T) {
+	// JSON context for a type with its own encoding
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+	cfg := &lineConfig{"Just testing", "8080"}
+	if err := conf.WriteStream(cfg); err != nil {
+		t.Fatal(err)
+	}
+	data, err := conf.ReadBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "name=Just testing\nport=8080\n" {
+		t.Errorf("Unexpected file contents: %q", data)
+	}
+}
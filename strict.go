@@ -0,0 +1,63 @@
+package conf
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Strict makes Read reject config files with unknown fields, which are
+// ignored by default, to catch typos in keys. It is supported by JSON,
+// JSONC, YAML and TOML and has no effect on other encodings.
+func (b *Builder) Strict() *Builder {
+	b.strict = true
+	return b
+}
+
+// strictUnmarshal maps formats to unmarshal funcs that reject unknown
+// fields.
+var strictUnmarshal = map[Format]UnmarshalFunc{
+	FormatJSON: jsonUnmarshalStrict,
+	FormatJSONC: func(data []byte, v interface{}) error {
+		return jsonUnmarshalStrict(stripJSONComments(data), v)
+	},
+	FormatYAML: yamlUnmarshalStrict,
+	FormatTOML: tomlUnmarshalStrict,
+}
+
+func jsonUnmarshalStrict(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("invalid data after top-level value")
+	}
+	return nil
+}
+
+func yamlUnmarshalStrict(data []byte, v interface{}) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(v); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+func tomlUnmarshalStrict(data []byte, v interface{}) error {
+	md, err := toml.Decode(string(data), v)
+	if err != nil {
+		return err
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return fmt.Errorf("unknown fields %v", undecoded)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package conf
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestStrict(t *testing.T) {
+	// The fixture has the misspelled field Numbr
+	conf := Build().Directory("testdata").File("unknown.json").JSON().Create()
+	var cfgRead TestConfig
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+
+	conf = Build().Directory("testdata").File("unknown.json").JSON().Strict().Create()
+	if err := conf.Read(&cfgRead); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+
+	conf = Build().Directory("testdata").JSON().Strict().Create()
+	cfgRead = TestConfig{}
+	if err := conf.Read(&cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	expected := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if cfgRead != expected {
+		t.Errorf("Configs differ: %v, %v", expected, cfgRead)
+	}
+}
+
+func TestStrictYAMLAndTOML(t *testing.T) {
+	dir := t.TempDir()
+	for name, b := range map[string]*Builder{
+		"YAML": Build().Directory(dir).YAML().Strict(),
+		"TOML": Build().Directory(dir).Strict().TOML(),
+	} {
+		conf := b.Create()
+		data := "string: test\nnumbr: 1\n"
+		if name == "TOML" {
+			data = "String = \"test\"\nNumbr = 1\n"
+		}
+		if err := ioutil.WriteFile(conf.Path(), []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+		var cfgRead TestConfig
+		if err := conf.Read(&cfgRead); err == nil {
+			t.Errorf("%s: Expected error for unknown field", name)
+		}
+	}
+}
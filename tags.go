@@ -0,0 +1,38 @@
+package conf
+
+import (
+	"reflect"
+	"strings"
+)
+
+// fieldKey returns the key of a struct field, which is either the name
+// given in its conf tag or the field name. ok is false if the field
+// should be skipped.
+func fieldKey(field reflect.StructField) (key string, ok bool) {
+	if field.PkgPath != "" {
+		return "", false
+	}
+	tag := field.Tag.Get("conf")
+	if tag == "-" {
+		return "", false
+	}
+	if i := strings.Index(tag, ","); i >= 0 {
+		tag = tag[:i]
+	}
+	if tag != "" {
+		return tag, true
+	}
+	return field.Name, true
+}
+
+// hasTagOption reports whether the conf tag of a struct field contains
+// the given option, e.g. `conf:",secret"`.
+func hasTagOption(field reflect.StructField, option string) bool {
+	opts := strings.Split(field.Tag.Get("conf"), ",")
+	for _, opt := range opts[1:] {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package conf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFieldKey(t *testing.T) {
+	type tagged struct {
+		Untagged string
+		Tagged   string `conf:"tag"`
+		Options  string `conf:"opt,secret"`
+		Empty    string `conf:",secret"`
+		Skipped  string `conf:"-"`
+		private  string
+	}
+	expected := []string{"Untagged", "tag", "opt", "Empty", "", ""}
+
+	typ := reflect.TypeOf(tagged{})
+	for i := 0; i < typ.NumField(); i++ {
+		key, ok := fieldKey(typ.Field(i))
+		if ok != (expected[i] != "") || key != expected[i] {
+			t.Errorf("%s: unexpected key %q", typ.Field(i).Name, key)
+		}
+	}
+}
+
+func TestHasTagOption(t *testing.T) {
+	type tagged struct {
+		Secret   string `conf:",secret"`
+		Named    string `conf:"name,required,secret"`
+		NoOption string `conf:"secret"`
+	}
+	expected := []bool{true, true, false}
+
+	typ := reflect.TypeOf(tagged{})
+	for i := 0; i < typ.NumField(); i++ {
+		if hasTagOption(typ.Field(i), "secret") != expected[i] {
+			t.Errorf("%s: expected %v", typ.Field(i).Name, expected[i])
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package conf
+
+import (
+	"fmt"
+	"os"
+)
+
+// Temp sets the directory to a new, unique directory in the temporary
+// directory of the operating system, whose name starts with prefix. The
+// directory is created immediately and stored in the TempDir field of the
+// context, so Cleanup can remove it. If the directory cannot be created,
+// CreateChecked returns the error, so use it instead of Create.
+func (b *Builder) Temp(prefix string) *Builder {
+	dir, err := os.MkdirTemp(os.TempDir(), prefix)
+	if err != nil {
+		b.err = fmt.Errorf("cannot create temporary directory: %w", err)
+		return b
+	}
+	b.ctx.Directory = dir
+	b.ctx.TempDir = dir
+	return b
+}
+
+// Cleanup removes the temporary directory created by Builder.Temp with all
+// its contents. It does nothing for contexts without one.
+func (c *Context) Cleanup() error {
+	if c.TempDir == "" {
+		return nil
+	}
+	if err := os.RemoveAll(c.TempDir); err != nil {
+		return err
+	}
+	c.TempDir = ""
+	return nil
+}
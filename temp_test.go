@@ -0,0 +1,48 @@
+package conf
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTemp(t *testing.T) {
+	// Initialize config context in a temporary directory
+	conf, err := Build().Temp("goconftest").JSON().CreateChecked()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := conf.TempDir
+	if conf.Directory != dir || !strings.HasPrefix(filepath.Base(dir), "goconftest") {
+		t.Errorf("Unexpected directory: %s", conf.Directory)
+	}
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		t.Fatalf("Expected temporary directory: %v", err)
+	}
+
+	cfg := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+	if err := conf.Write(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conf.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Expected directory to be removed: %v", err)
+	}
+
+	// Without a temporary directory, Cleanup does nothing
+	if err := Build().Directory(t.TempDir()).Create().Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTempError(t *testing.T) {
+	t.Setenv("TMPDIR", filepath.Join(t.TempDir(), "missing"))
+	if _, err := Build().Temp("goconftest").JSON().CreateChecked(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected missing temporary directory, got %v", err)
+	}
+}
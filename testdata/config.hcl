@@ -0,0 +1,7 @@
+# Example config
+string = "Just testing"
+number = 123
+
+sub {
+  field = "test"
+}
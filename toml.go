@@ -0,0 +1,23 @@
+package conf
+
+import (
+	"bytes"
+
+	"github.com/BurntSushi/toml"
+)
+
+func tomlMarshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// TOML sets the encoding to the TOML format.
+func (b *Builder) TOML() *Builder {
+	if b.ctx.File == "" {
+		b.ctx.File = "config.toml"
+	}
+	return b.codec(FormatTOML, tomlMarshal, toml.Unmarshal)
+}
@@ -0,0 +1,30 @@
+//go:build unix
+
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestDefaultModesWithoutUmask(t *testing.T) {
+	old := syscall.Umask(0)
+	defer syscall.Umask(old)
+
+	conf := Build().Directory(filepath.Join(t.TempDir(), "sub")).JSON().Create()
+	if err := conf.Write(TestConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{conf.Directory, conf.Path()} {
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := fi.Mode().Perm(); perm&0022 != 0 {
+			t.Errorf("%s is group or world writable: %v", path, perm)
+		}
+	}
+}
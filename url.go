@@ -0,0 +1,55 @@
+package conf
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// HTTPTimeout sets the timeout of requests made by ReadURL.
+func (b *Builder) HTTPTimeout(d time.Duration) *Builder {
+	b.ctx.HTTPTimeout = d
+	return b
+}
+
+// ReadURL fetches the config from url via HTTP(S) and decodes it into
+// the value pointed to by conf, like Read does for the config file.
+// Responses with a non-2xx status code are returned as errors.
+func (c *Context) ReadURL(url string, conf interface{}) error {
+	return c.ReadURLContext(context.Background(), url, conf)
+}
+
+// ReadURLContext fetches the config from url like ReadURL, but aborts the
+// request when ctx is done.
+func (c *Context) ReadURLContext(ctx context.Context, url string, conf interface{}) error {
+	if err := c.readURL(ctx, url, conf); err != nil {
+		return &ConfigError{"read", url, err}
+	}
+	return nil
+}
+
+func (c *Context) readURL(ctx context.Context, url string, conf interface{}) error {
+	if c.Defaults != nil {
+		if err := c.applyDefaults(conf, c.Defaults); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: c.HTTPTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if err := c.Decode(resp.Body, conf); err != nil {
+		return err
+	}
+	return c.validate(conf)
+}
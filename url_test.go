@@ -0,0 +1,35 @@
+package conf
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/config.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"String": "remote", "Number": 5}`))
+	}))
+	defer srv.Close()
+
+	conf := Build().JSON().HTTPTimeout(5 * time.Second).Create()
+
+	var cfgRead TestConfig
+	if err := conf.ReadURL(srv.URL+"/config.json", &cfgRead); err != nil {
+		t.Fatal(err)
+	}
+	if cfgRead.String != "remote" || cfgRead.Number != 5 {
+		t.Errorf("Unexpected config: %v", cfgRead)
+	}
+
+	err := conf.ReadURL(srv.URL+"/missing.json", &cfgRead)
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected 404 error, got %v", err)
+	}
+}
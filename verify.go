@@ -0,0 +1,65 @@
+package conf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrRoundTrip is returned by CreateChecked if the encoding of the context
+// does not decode the sample to the same value.
+var ErrRoundTrip = errors.New("Codec does not round-trip")
+
+// VerifyCodec sets a sample config that CreateChecked encodes and decodes
+// again to verify that the marshaller and unmarshaller of the context
+// match. The sample should have non-zero fields to be meaningful.
+func (b *Builder) VerifyCodec(sample interface{}) *Builder {
+	b.sample = sample
+	return b
+}
+
+// CreateChecked creates the context like Create, but also returns errors
+// of the builder, e.g. of Temp, and verifies the encoding with the sample
+// set by VerifyCodec, if any. The context is returned even if this fails.
+func (b *Builder) CreateChecked() (*Context, error) {
+	c := b.Create()
+	if b.err != nil {
+		return c, b.err
+	}
+	if b.sample == nil {
+		return c, nil
+	}
+	return c, c.verifyCodec(b.sample)
+}
+
+// verifyCodec encodes sample, decodes it into a new value of its type and
+// compares the encodings of both.
+func (c *Context) verifyCodec(sample interface{}) error {
+	if c.Marshal == nil {
+		return ErrNoMarshal
+	}
+	if c.Unmarshal == nil {
+		return ErrNoUnmarshal
+	}
+	data, err := c.Marshal(sample)
+	if err != nil {
+		return err
+	}
+	t := reflect.TypeOf(sample)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	decoded := reflect.New(t)
+	if err := c.Unmarshal(data, decoded.Interface()); err != nil {
+		return fmt.Errorf("%w: %v", ErrRoundTrip, err)
+	}
+	again, err := c.Marshal(decoded.Elem().Interface())
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(data, again) {
+		return fmt.Errorf("%w: %q != %q", ErrRoundTrip, data, again)
+	}
+	return nil
+}
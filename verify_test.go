@@ -0,0 +1,41 @@
+package conf
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestVerifyCodec(t *testing.T) {
+	sample := TestConfig{"Just testing", 123, struct{ Field string }{"test"}}
+
+	conf, err := Build().JSON().VerifyCodec(sample).CreateChecked()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf == nil {
+		t.Fatal("Expected context")
+	}
+
+	// An unmarshaller that drops the Number field
+	broken := func(data []byte, v interface{}) error {
+		if err := json.Unmarshal(data, v); err != nil {
+			return err
+		}
+		v.(*TestConfig).Number = 0
+		return nil
+	}
+	_, err = Build().Marshaller(json.Marshal, broken).VerifyCodec(&sample).CreateChecked()
+	if !errors.Is(err, ErrRoundTrip) {
+		t.Errorf("Expected ErrRoundTrip, got %v", err)
+	}
+
+	// An unmarshaller that fails
+	failing := func(data []byte, v interface{}) error {
+		return errors.New("broken")
+	}
+	_, err = Build().Marshaller(json.Marshal, failing).VerifyCodec(sample).CreateChecked()
+	if !errors.Is(err, ErrRoundTrip) {
+		t.Errorf("Expected ErrRoundTrip, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package conf
+
+import (
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watch watches the config file for changes and reads it into the value
+// pointed to by conf whenever it changes. onChange is called with the
+// result of each read, or with errors of the underlying watcher.
+//
+// The directory of the config file is watched instead of the file itself,
+// so replacing the file, as editors and Write do, is picked up as well.
+// The returned stop function stops watching, as does Close.
+func (c *Context) Watch(conf interface{}, onChange func(error)) (stop func(), err error) {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := w.Add(c.Directory); err != nil {
+		w.Close()
+		return nil, err
+	}
+	path := c.Path()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case ev, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(ev.Name) != path {
+					continue
+				}
+				if ev.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+					continue
+				}
+				onChange(c.Read(conf))
+			case err, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+				onChange(err)
+			}
+		}
+	}()
+	var once sync.Once
+	stopWatcher := func() error {
+		once.Do(func() {
+			w.Close()
+			<-done
+		})
+		return nil
+	}
+	remove := c.onClose(stopWatcher)
+	return func() {
+		remove()
+		stopWatcher()
+	}, nil
+}
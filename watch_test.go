@@ -0,0 +1,37 @@
+package conf
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	conf := Build().Directory(t.TempDir()).JSON().Create()
+	if err := conf.Write(TestConfig{Number: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfgRead TestConfig
+	changed := make(chan error, 100)
+	stop, err := conf.Watch(&cfgRead, func(err error) {
+		changed <- err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	for i := 2; i <= 3; i++ {
+		if err := conf.Write(TestConfig{Number: i}); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case err := <-changed:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for change")
+		}
+	}
+}
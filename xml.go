@@ -0,0 +1,17 @@
+package conf
+
+import (
+	"encoding/xml"
+)
+
+func xmlMarshalIndent(v interface{}) ([]byte, error) {
+	return xml.MarshalIndent(v, "", "    ")
+}
+
+// XML sets the encoding to the XML format.
+func (b *Builder) XML() *Builder {
+	if b.ctx.File == "" {
+		b.ctx.File = "config.xml"
+	}
+	return b.codec(FormatXML, xmlMarshalIndent, xml.Unmarshal)
+}
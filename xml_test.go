@@ -0,0 +1,46 @@
+package conf
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+type XMLTestConfig struct {
+	XMLName xml.Name `xml:"config"`
+	Name    string   `xml:"name,attr"`
+	Number  int      `xml:"number"`
+	Sub     struct {
+		Field string `xml:"field"`
+	} `xml:"sub"`
+}
+
+func TestXML(t *testing.T) {
+	// Initialize config context
+	conf := Build().Directory(t.TempDir()).XML().Create()
+	if conf.File != "config.xml" {
+		t.Errorf("Unexpected file name: %s", conf.File)
+	}
+
+	// Example config
+	var cfg XMLTestConfig
+	cfg.Name = "Just testing"
+	cfg.Number = 123
+	cfg.Sub.Field = "test"
+
+	// Write config
+	err := conf.Write(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Read config
+	var cfgRead XMLTestConfig
+	err = conf.Read(&cfgRead)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Name != cfgRead.Name || cfg.Number != cfgRead.Number || cfg.Sub != cfgRead.Sub {
+		t.Errorf("Configs differ: %v, %v", cfg, cfgRead)
+	}
+}
@@ -0,0 +1,13 @@
+package conf
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// YAML sets the encoding to the YAML format.
+func (b *Builder) YAML() *Builder {
+	if b.ctx.File == "" {
+		b.ctx.File = "config.yaml"
+	}
+	return b.codec(FormatYAML, yaml.Marshal, yaml.Unmarshal)
+}